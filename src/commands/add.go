@@ -9,34 +9,48 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// Declare runPrompt at package level for production use
-var runPrompt = func(p promptui.Prompt) (string, error) {
-	return p.Run()
-}
-
 func NewAddCommand(cfg config.Manager) *cobra.Command {
-	return &cobra.Command{
+	var storeIDFlag, aliasFlag, projectDirFlag string
+	var noInput bool
+
+	cmd := &cobra.Command{
 		Use:   "add",
 		Short: "Add a new Shopify store configuration",
+		Long: `Add a new Shopify store configuration.
+
+Values passed as flags are not prompted for. With --no-input, or when stdin
+is not a terminal, stm never prompts and fails if --store-id or
+--project-dir is missing; the alias defaults to the store ID.
+
+The project directory must exist; when running interactively stm offers
+to create it. stm warns when it is outside the workspace, absolute inside
+the workspace, or already used by another store.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			interactive := !noInput && stdinIsTerminal()
+
 			// Store ID prompt
 			storePrompt := promptui.Prompt{
 				Label:    "Enter the Shopify store ID",
-				Validate: notEmptyValidator,
+				Validate: storeIDValidator(cfg),
 			}
-			storeID, err := runPrompt(storePrompt)
+			storeID, err := flagOrPrompt(storeIDFlag, "store-id", interactive, storePrompt)
 			if err != nil {
 				return err
 			}
 
-			// Alias prompt
+			// Alias prompt, optional even without input
 			aliasPrompt := promptui.Prompt{
-				Label:   "Enter an alias for the store (optional)",
-				Default: storeID,
+				Label:    "Enter an alias for the store (optional)",
+				Default:  storeID,
+				Validate: aliasValidator(cfg),
 			}
-			alias, err := runPrompt(aliasPrompt)
-			if err != nil {
-				return err
+			alias := aliasFlag
+			if alias != "" || interactive {
+				alias, err = flagOrPrompt(aliasFlag, "alias", interactive, aliasPrompt)
+				if err != nil {
+					return err
+				}
 			}
 			if alias == "" {
 				alias = storeID
@@ -47,19 +61,83 @@ func NewAddCommand(cfg config.Manager) *cobra.Command {
 				Label:    "Enter the project directory path",
 				Validate: notEmptyValidator,
 			}
-			projectDir, err := runPrompt(dirPrompt)
+			projectDir, err := flagOrPrompt(projectDirFlag, "project-dir", interactive, dirPrompt)
 			if err != nil {
 				return err
 			}
 
+			if err := checkProjectDir(cmd, cfg, alias, projectDir, interactive); err != nil {
+				return err
+			}
+
 			if err := cfg.AddStore(storeID, alias, projectDir); err != nil {
 				return err
 			}
 
-			fmt.Printf("Store %s added successfully\n", alias)
+			fmt.Fprintf(cmd.OutOrStdout(), "Store %s added successfully\n", alias)
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&storeIDFlag, "store-id", "", "Shopify store ID (e.g. my-store.myshopify.com)")
+	cmd.Flags().StringVar(&aliasFlag, "alias", "", "Alias for the store (defaults to the store ID)")
+	cmd.Flags().StringVar(&projectDirFlag, "project-dir", "", "Project directory, relative to the workspace")
+	cmd.Flags().BoolVar(&noInput, "no-input", false, "Never prompt; fail if a required value is missing")
+
+	return cmd
+}
+
+// flagOrPrompt returns the flag value after running the prompt's validator
+// on it. Without a flag value it prompts, or fails when not interactive.
+func flagOrPrompt(value, flagName string, interactive bool, prompt promptui.Prompt) (string, error) {
+	if value != "" {
+		if prompt.Validate != nil {
+			if err := prompt.Validate(value); err != nil {
+				return "", fmt.Errorf("invalid --%s: %w", flagName, err)
+			}
+		}
+		return value, nil
+	}
+
+	if !interactive {
+		return "", fmt.Errorf("--%s is required when not running interactively", flagName)
+	}
+
+	return runPrompt(prompt)
+}
+
+// storeIDValidator rejects malformed store domains and stores that are
+// already configured under another alias.
+func storeIDValidator(cfg config.Manager) func(string) error {
+	return func(input string) error {
+		storeID, err := config.NormalizeStoreID(input)
+		if err != nil {
+			return err
+		}
+		for _, store := range cfg.ListStores() {
+			if existing, err := config.NormalizeStoreID(store.StoreID); err == nil && existing == storeID {
+				return fmt.Errorf("store %q is already configured as %q", storeID, store.Alias)
+			}
+		}
+		return nil
+	}
+}
+
+// aliasValidator rejects aliases that are unsafe or already in use. An
+// empty alias is accepted because it falls back to the store ID.
+func aliasValidator(cfg config.Manager) func(string) error {
+	return func(input string) error {
+		if input == "" {
+			return nil
+		}
+		if err := config.ValidateAlias(input); err != nil {
+			return err
+		}
+		if cfg.GetStore(input) != nil {
+			return fmt.Errorf("store with alias %q already exists", input)
+		}
+		return nil
+	}
 }
 
 func notEmptyValidator(input string) error {
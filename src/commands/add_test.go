@@ -2,12 +2,28 @@ package commands
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/manifoldco/promptui"
 )
 
+// setupAddWorkspace sets a workspace holding the project directories the
+// add tests use.
+func setupAddWorkspace(t *testing.T, h *testHelper) string {
+	t.Helper()
+	workspace := t.TempDir()
+	for _, dir := range []string{"test-dir", "existing-dir"} {
+		if err := os.Mkdir(filepath.Join(workspace, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.mock.SetWorkspace(workspace)
+	return workspace
+}
+
 func TestAddCommand(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -88,7 +104,7 @@ func TestAddCommand(t *testing.T) {
 				"Enter the Shopify store ID": "",
 			},
 			wantErr: true,
-			errMsg:  "value cannot be empty",
+			errMsg:  "store ID cannot be empty",
 		},
 		{
 			name: "empty project directory",
@@ -100,6 +116,40 @@ func TestAddCommand(t *testing.T) {
 			wantErr: true,
 			errMsg:  "value cannot be empty",
 		},
+		{
+			name: "store ID with scheme",
+			promptResponses: map[string]string{
+				"Enter the Shopify store ID": "https://test-store.myshopify.com",
+			},
+			wantErr: true,
+			errMsg:  "must be a domain, not a URL",
+		},
+		{
+			name: "store ID already configured",
+			promptResponses: map[string]string{
+				"Enter the Shopify store ID": "existing-store.myshopify.com",
+			},
+			wantErr: true,
+			errMsg:  "already configured as \"existing-alias\"",
+		},
+		{
+			name: "alias with whitespace",
+			promptResponses: map[string]string{
+				"Enter the Shopify store ID":              "test-store",
+				"Enter an alias for the store (optional)": "test alias",
+			},
+			wantErr: true,
+			errMsg:  "must not contain whitespace",
+		},
+		{
+			name: "alias already in use",
+			promptResponses: map[string]string{
+				"Enter the Shopify store ID":              "test-store",
+				"Enter an alias for the store (optional)": "existing-alias",
+			},
+			wantErr: true,
+			errMsg:  "store with alias \"existing-alias\" already exists",
+		},
 		{
 			name: "default alias",
 			promptResponses: map[string]string{
@@ -127,6 +177,9 @@ func TestAddCommand(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			h := newTestHelper(t)
+			setupAddWorkspace(t, h)
+			h.mock.AddStore("existing-store", "existing-alias", "existing-dir")
+			defer MockStdinIsTerminal(true)()
 
 			// Mock the prompt responses and errors
 			cleanup := MockPrompt(func(p promptui.Prompt) (string, error) {
@@ -181,6 +234,210 @@ func TestAddCommand(t *testing.T) {
 	}
 }
 
+func TestAddCommand_NonInteractive(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		isTerminal bool
+		responses  map[string]string
+		wantErr    bool
+		errMsg     string
+		wantStore  string
+		wantAlias  string
+		wantDir    string
+	}{
+		{
+			name:       "all values from flags",
+			args:       []string{"add", "--store-id", "test-store", "--alias", "test-alias", "--project-dir", "test-dir"},
+			isTerminal: true,
+			wantStore:  "test-store",
+			wantAlias:  "test-alias",
+			wantDir:    "test-dir",
+		},
+		{
+			name:      "alias defaults to store ID without a terminal",
+			args:      []string{"add", "--store-id", "test-store", "--project-dir", "test-dir"},
+			wantStore: "test-store",
+			wantAlias: "test-store",
+			wantDir:   "test-dir",
+		},
+		{
+			name:       "prompt only for missing values",
+			args:       []string{"add", "--store-id", "test-store"},
+			isTerminal: true,
+			responses: map[string]string{
+				"Enter an alias for the store (optional)": "test-alias",
+				"Enter the project directory path":        "test-dir",
+			},
+			wantStore: "test-store",
+			wantAlias: "test-alias",
+			wantDir:   "test-dir",
+		},
+		{
+			name:       "no input with missing store ID",
+			args:       []string{"add", "--no-input", "--project-dir", "test-dir"},
+			isTerminal: true,
+			wantErr:    true,
+			errMsg:     "--store-id is required when not running interactively",
+		},
+		{
+			name:    "no terminal with missing project directory",
+			args:    []string{"add", "--store-id", "test-store"},
+			wantErr: true,
+			errMsg:  "--project-dir is required when not running interactively",
+		},
+		{
+			name:    "invalid store ID flag",
+			args:    []string{"add", "--store-id", "https://test-store.myshopify.com", "--project-dir", "test-dir"},
+			wantErr: true,
+			errMsg:  "invalid --store-id: store ID",
+		},
+		{
+			name:    "alias flag already in use",
+			args:    []string{"add", "--store-id", "test-store", "--alias", "existing-alias", "--project-dir", "test-dir"},
+			wantErr: true,
+			errMsg:  "invalid --alias: store with alias \"existing-alias\" already exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t)
+			setupAddWorkspace(t, h)
+			h.mock.AddStore("existing-store", "existing-alias", "existing-dir")
+			defer MockStdinIsTerminal(tt.isTerminal)()
+
+			defer MockPrompt(func(p promptui.Prompt) (string, error) {
+				if response, ok := tt.responses[p.Label.(string)]; ok {
+					return response, nil
+				}
+				return "", errors.New("unexpected prompt")
+			})()
+
+			cmd := NewAddCommand(h.mock)
+			h.setupCommand(cmd)
+
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			store := h.mock.GetStore(tt.wantAlias)
+			if store == nil {
+				t.Fatalf("store %q was not added", tt.wantAlias)
+			}
+			if store.StoreID != tt.wantStore || store.ProjectDir != tt.wantDir {
+				t.Errorf("store = %+v, want store ID %s and project dir %s", store, tt.wantStore, tt.wantDir)
+			}
+		})
+	}
+}
+
+func TestAddCommand_ProjectDirChecks(t *testing.T) {
+	outside := t.TempDir()
+
+	tests := []struct {
+		name       string
+		projectDir string
+		isTerminal bool
+		create     bool
+		wantErr    string
+		contains   string
+	}{
+		{
+			name:       "missing directory is created",
+			projectDir: "new-dir",
+			isTerminal: true,
+			create:     true,
+			contains:   "Created ",
+		},
+		{
+			name:       "missing directory not created",
+			projectDir: "new-dir",
+			isTerminal: true,
+			wantErr:    "directory",
+		},
+		{
+			name:       "missing directory without a terminal",
+			projectDir: "new-dir",
+			wantErr:    "does not exist; create it first",
+		},
+		{
+			name:       "outside the workspace",
+			projectDir: outside,
+			contains:   "Warning: project directory " + outside + " is outside the workspace",
+		},
+		{
+			name:       "absolute inside the workspace",
+			projectDir: "<workspace>/test-dir",
+			contains:   "use the relative path test-dir",
+		},
+		{
+			name:       "shared with another store",
+			projectDir: "existing-dir",
+			contains:   "Warning: store existing-alias uses the same project directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t)
+			workspace := setupAddWorkspace(t, h)
+			h.mock.AddStore("existing-store", "existing-alias", "existing-dir")
+			defer MockStdinIsTerminal(tt.isTerminal)()
+			defer MockPrompt(func(p promptui.Prompt) (string, error) {
+				if p.IsConfirm && strings.HasPrefix(p.Label.(string), "Directory ") {
+					if tt.create {
+						return "y", nil
+					}
+					return "", promptui.ErrAbort
+				}
+				return "", nil
+			})()
+
+			projectDir := strings.Replace(tt.projectDir, "<workspace>", workspace, 1)
+			h.setupCommand(NewAddCommand(h.mock))
+			h.cmd.SetArgs([]string{"add", "--store-id", "test-store", "--alias", "test-alias", "--project-dir", projectDir})
+			err := h.cmd.Execute()
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want error containing %q", err, tt.wantErr)
+				}
+				if h.mock.GetStore("test-alias") != nil {
+					t.Error("store was added")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if h.mock.GetStore("test-alias") == nil {
+				t.Error("store was not added")
+			}
+			if !strings.Contains(h.output.String(), tt.contains) {
+				t.Errorf("output = %q, want %q", h.output.String(), tt.contains)
+			}
+			if tt.create {
+				if _, err := os.Stat(filepath.Join(workspace, tt.projectDir)); err != nil {
+					t.Errorf("project directory was not created: %v", err)
+				}
+			}
+		})
+	}
+}
+
 func TestNotEmptyValidator(t *testing.T) {
 	tests := []struct {
 		name    string
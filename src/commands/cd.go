@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/spf13/cobra"
+)
+
+func NewCdCommand(cfg config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "cd [store-alias]",
+		Short: "Print a store's project directory",
+		Long: `Print the resolved project directory of a store.
+
+A program cannot change the directory of the shell that started it, so to
+actually change directory load the shell wrapper from "stm shell-init":
+
+  eval "$(stm shell-init bash)"`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeStoreAliases(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, _, err := storeArg(cfg, args, 0)
+			if err != nil {
+				return err
+			}
+
+			dir, err := existingProjectDir(cfg, store)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), dir)
+			return nil
+		},
+	}
+}
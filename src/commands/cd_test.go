@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCdCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantDir string
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "print project directory",
+			args:    []string{"cd", "test-alias"},
+			wantDir: "test-dir",
+		},
+		{
+			name:    "missing project directory",
+			args:    []string{"cd", "missing-alias"},
+			wantErr: true,
+			errMsg:  "does not exist",
+		},
+		{
+			name:    "store not found",
+			args:    []string{"cd", "invalid-store"},
+			wantErr: true,
+			errMsg:  "store with alias \"invalid-store\" not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t)
+
+			workspace := t.TempDir()
+			if err := os.Mkdir(filepath.Join(workspace, "test-dir"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			h.mock.SetWorkspace(workspace)
+			h.mock.AddStore("test-store", "test-alias", "test-dir")
+			h.mock.AddStore("missing-store", "missing-alias", "missing-dir")
+
+			cmd := NewCdCommand(h.mock)
+			h.setupCommand(cmd)
+
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			want := filepath.Join(workspace, tt.wantDir) + "\n"
+			if got := h.output.String(); got != want {
+				t.Errorf("output = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCdCommand_CompletesAliases(t *testing.T) {
+	h := newTestHelper(t)
+	h.mock.AddStore("alpha-store", "alpha", "alpha-dir")
+	h.mock.AddStore("beta-store", "beta", "beta-dir")
+
+	cmd := NewCdCommand(h.mock)
+	completions, directive := cmd.ValidArgsFunction(cmd, nil, "al")
+
+	if len(completions) != 1 || completions[0] != "alpha\talpha-store" {
+		t.Errorf("completions = %v, want only alpha", completions)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v, want NoFileComp", directive)
+	}
+
+	completions, _ = cmd.ValidArgsFunction(cmd, []string{"alpha"}, "")
+	if len(completions) != 0 {
+		t.Errorf("completions after alias = %v, want none", completions)
+	}
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/colinxr/shopify-theme-manager/shopify"
+	"github.com/spf13/cobra"
+)
+
+type completionFunc func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective)
+
+// completeStoreAliases offers configured store aliases for the first
+// positional argument of alias-taking commands.
+func completeStoreAliases(cfg config.Manager) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return storeAliasCompletions(cfg, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeStoreThenTheme offers store aliases for the first argument and
+// that store's theme IDs for the second.
+func completeStoreThenTheme(cfg config.Manager, runner shopify.Runner) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		switch len(args) {
+		case 0:
+			return storeAliasCompletions(cfg, toComplete), cobra.ShellCompDirectiveNoFileComp
+		case 1:
+			return themeCompletions(cmd, cfg, runner, args[0], toComplete), cobra.ShellCompDirectiveNoFileComp
+		default:
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+}
+
+func storeAliasCompletions(cfg config.Manager, toComplete string) []string {
+	var aliases []string
+	for _, store := range cfg.ListStores() {
+		if strings.HasPrefix(store.Alias, toComplete) {
+			aliases = append(aliases, store.Alias+"\t"+store.StoreID)
+		}
+	}
+	return aliases
+}
+
+// themeCompletions matches toComplete against theme IDs and names, always
+// completing to the ID with the name and role as the description.
+func themeCompletions(cmd *cobra.Command, cfg config.Manager, runner shopify.Runner, alias, toComplete string) []string {
+	store := cfg.GetStore(alias)
+	if store == nil {
+		return nil
+	}
+
+	themes, err := cachedThemes(cmd.Context(), runner, store.StoreID)
+	if err != nil {
+		return nil
+	}
+
+	var completions []string
+	lower := strings.ToLower(toComplete)
+	for _, theme := range themes {
+		id := strconv.FormatInt(theme.ID, 10)
+		if !strings.HasPrefix(id, toComplete) && !strings.HasPrefix(strings.ToLower(theme.Name), lower) {
+			continue
+		}
+		completions = append(completions, fmt.Sprintf("%s\t%s (%s)", id, theme.Name, theme.Role))
+	}
+	return completions
+}
+
+func NewCompletionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion <bash|zsh|fish|powershell>",
+		Short: "Generate a shell completion script",
+		Long: `Generate a completion script for stm. Store aliases and theme IDs are
+completed from your stm config and the Shopify CLI.
+
+  # bash
+  source <(stm completion bash)
+
+  # zsh
+  stm completion zsh > "${fpath[1]}/_stm"
+
+  # fish
+  stm completion fish > ~/.config/fish/completions/stm.fish
+
+  # PowerShell
+  stm completion powershell | Out-String | Invoke-Expression`,
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		DisableFlagsInUseLine: true,
+		Annotations:           map[string]string{worksWithoutConfig: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			default:
+				return root.GenPowerShellCompletionWithDesc(out)
+			}
+		},
+	}
+}
@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const themeListJSON = `[
+  {"id": 111, "name": "Dawn", "role": "live"},
+  {"id": 222, "name": "Dawn staging", "role": "unpublished"},
+  {"id": 333, "name": "Refresh", "role": "development"}
+]`
+
+func TestCompleteStoreThenTheme(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		toComplete string
+		want       []string
+	}{
+		{
+			name:       "store aliases",
+			toComplete: "",
+			want:       []string{"alpha\talpha-store", "beta\tbeta-store"},
+		},
+		{
+			name:       "store alias prefix",
+			toComplete: "b",
+			want:       []string{"beta\tbeta-store"},
+		},
+		{
+			name: "all themes",
+			args: []string{"alpha"},
+			want: []string{"111\tDawn (live)", "222\tDawn staging (unpublished)", "333\tRefresh (development)"},
+		},
+		{
+			name:       "theme ID prefix",
+			args:       []string{"alpha"},
+			toComplete: "3",
+			want:       []string{"333\tRefresh (development)"},
+		},
+		{
+			name:       "theme name prefix",
+			args:       []string{"alpha"},
+			toComplete: "daw",
+			want:       []string{"111\tDawn (live)", "222\tDawn staging (unpublished)"},
+		},
+		{
+			name: "unknown store",
+			args: []string{"missing"},
+			want: nil,
+		},
+		{
+			name: "no further arguments",
+			args: []string{"alpha", "111"},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t)
+			h.mock.AddStore("alpha-store", "alpha", "alpha-dir")
+			h.mock.AddStore("beta-store", "beta", "beta-dir")
+
+			defer MockThemeCacheDir(t.TempDir())()
+			h.runner.Stdout = themeListJSON
+
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+
+			complete := completeStoreThenTheme(h.mock, h.runner)
+			got, directive := complete(cmd, tt.args, tt.toComplete)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("completions = %q, want %q", got, tt.want)
+			}
+			if directive != cobra.ShellCompDirectiveNoFileComp {
+				t.Errorf("directive = %v, want NoFileComp", directive)
+			}
+		})
+	}
+}
+
+func TestCachedThemes(t *testing.T) {
+	cacheDir := t.TempDir()
+	defer MockThemeCacheDir(cacheDir)()
+
+	runner := NewMockRunner()
+	runner.Stdout = themeListJSON
+	ctx := context.Background()
+
+	themes, err := cachedThemes(ctx, runner, "alpha-store")
+	if err != nil {
+		t.Fatalf("cachedThemes() error = %v", err)
+	}
+	if len(themes) != 3 || themes[0].ID != 111 || themes[0].Role != "live" {
+		t.Errorf("themes = %+v", themes)
+	}
+	wantArgs := []string{"theme", "list", "--store", "alpha-store", "--json"}
+	if got := runner.LastCall().Args; !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("executed args = %v, want %v", got, wantArgs)
+	}
+
+	// A fresh cache is reused
+	if _, err := cachedThemes(ctx, runner, "alpha-store"); err != nil {
+		t.Fatal(err)
+	}
+	if calls := len(runner.Calls()); calls != 1 {
+		t.Errorf("shopify called %d times, want 1", calls)
+	}
+
+	// An expired cache is refreshed
+	stale := time.Now().Add(-2 * themeCacheTTL)
+	if err := os.Chtimes(filepath.Join(cacheDir, "alpha-store.json"), stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cachedThemes(ctx, runner, "alpha-store"); err != nil {
+		t.Fatal(err)
+	}
+	if calls := len(runner.Calls()); calls != 2 {
+		t.Errorf("shopify called %d times, want 2", calls)
+	}
+}
+
+func TestCachedThemes_CommandFailure(t *testing.T) {
+	defer MockThemeCacheDir(t.TempDir())()
+	runner := NewMockRunner()
+	runner.Err = errors.New("exit status 1")
+
+	if _, err := cachedThemes(context.Background(), runner, "alpha-store"); err == nil {
+		t.Error("expected error from failed command but got none")
+	}
+}
+
+func TestCompletionCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+		errMsg  string
+	}{
+		{name: "bash", args: []string{"completion", "bash"}, want: "bash completion V2 for stm"},
+		{name: "zsh", args: []string{"completion", "zsh"}, want: "#compdef stm"},
+		{name: "fish", args: []string{"completion", "fish"}, want: "fish completion for stm"},
+		{name: "powershell", args: []string{"completion", "powershell"}, want: "powershell completion for stm"},
+		{
+			name:    "unsupported shell",
+			args:    []string{"completion", "tcsh"},
+			wantErr: true,
+			errMsg:  "invalid argument \"tcsh\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t)
+
+			rootCmd := NewRootCommand(h.mock, h.runner)
+			rootCmd.SetOut(h.output)
+			rootCmd.SetErr(h.output)
+			rootCmd.SetArgs(tt.args)
+			err := rootCmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if !strings.Contains(h.output.String(), tt.want) {
+				t.Errorf("output does not contain %q", tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/spf13/cobra"
+)
+
+func NewConfigCommand(cfg config.Manager) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect and maintain the stm config file",
+	}
+
+	cmd.AddCommand(
+		newConfigPathCommand(cfg),
+		newConfigMigrateCommand(cfg),
+	)
+	return cmd
+}
+
+func newConfigPathCommand(cfg config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:         "path",
+		Short:       "Print the location of the config file",
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{worksWithoutConfig: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Fprintln(cmd.OutOrStdout(), cfg.ConfigPath())
+			return nil
+		},
+	}
+}
+
+func newConfigMigrateCommand(cfg config.Manager) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade the config file to the current schema version",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plan, err := cfg.MigrationPlan()
+			if err != nil {
+				return fmt.Errorf("failed to plan config migration: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(plan.Steps) == 0 {
+				fmt.Fprintf(out, "Config is already at version %d\n", plan.ToVersion)
+				return nil
+			}
+
+			fmt.Fprintf(out, "Config version %d -> %d\n", plan.FromVersion, plan.ToVersion)
+			for _, step := range plan.Steps {
+				fmt.Fprintf(out, "  v%d -> v%d: %s\n", step.From, step.From+1, step.Description)
+			}
+
+			if dryRun {
+				fmt.Fprintln(out, "\nDry run, no changes written. Migrated config:")
+				fmt.Fprintln(out, string(plan.Result))
+				return nil
+			}
+
+			if err := cfg.Migrate(); err != nil {
+				return fmt.Errorf("failed to migrate config: %w", err)
+			}
+
+			fmt.Fprintf(out, "Config migrated to version %d\n", plan.ToVersion)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would change without writing the config")
+	return cmd
+}
@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+)
+
+func TestConfigPathCommand(t *testing.T) {
+	h := newTestHelper(t)
+	h.mock.(*MockConfig).configPath = "/home/user/.config/shopify-theme-manager/config.json"
+
+	cmd := NewConfigCommand(h.mock)
+	h.setupCommand(cmd)
+
+	h.cmd.SetArgs([]string{"config", "path"})
+	if err := h.cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "/home/user/.config/shopify-theme-manager/config.json\n"
+	if got := h.output.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestConfigMigrateCommand(t *testing.T) {
+	pending := &config.MigrationPlan{
+		FromVersion: 0,
+		ToVersion:   1,
+		Steps: []config.Migration{
+			{From: 0, Description: "normalize store IDs"},
+		},
+		Result: []byte(`{"version": 1}`),
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		plan        *config.MigrationPlan
+		migrateErr  error
+		wantErr     bool
+		errMsg      string
+		wantOutput  []string
+		wantMigrate bool
+	}{
+		{
+			name:       "already current",
+			args:       []string{"config", "migrate"},
+			plan:       &config.MigrationPlan{FromVersion: 1, ToVersion: 1},
+			wantOutput: []string{"Config is already at version 1"},
+		},
+		{
+			name:        "migrate",
+			args:        []string{"config", "migrate"},
+			plan:        pending,
+			wantOutput:  []string{"Config version 0 -> 1", "v0 -> v1: normalize store IDs", "Config migrated to version 1"},
+			wantMigrate: true,
+		},
+		{
+			name:       "dry run",
+			args:       []string{"config", "migrate", "--dry-run"},
+			plan:       pending,
+			wantOutput: []string{"v0 -> v1: normalize store IDs", "Dry run, no changes written", `{"version": 1}`},
+		},
+		{
+			name:        "migration fails",
+			args:        []string{"config", "migrate"},
+			plan:        pending,
+			migrateErr:  errors.New("disk full"),
+			wantErr:     true,
+			errMsg:      "failed to migrate config: disk full",
+			wantMigrate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t)
+			mock := &mockConfigWithMigrations{
+				MockConfig: h.mock.(*MockConfig),
+				plan:       tt.plan,
+				migrateErr: tt.migrateErr,
+			}
+
+			cmd := NewConfigCommand(mock)
+			h.setupCommand(cmd)
+
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if mock.migrated != tt.wantMigrate {
+				t.Errorf("migrated = %v, want %v", mock.migrated, tt.wantMigrate)
+			}
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(h.output.String(), want) {
+					t.Errorf("output = %q, want to contain %q", h.output.String(), want)
+				}
+			}
+		})
+	}
+}
+
+// mockConfigWithMigrations wraps MockConfig to report pending migrations
+type mockConfigWithMigrations struct {
+	*MockConfig
+	plan       *config.MigrationPlan
+	migrateErr error
+	migrated   bool
+}
+
+func (m *mockConfigWithMigrations) MigrationPlan() (*config.MigrationPlan, error) {
+	return m.plan, nil
+}
+
+func (m *mockConfigWithMigrations) Migrate() error {
+	m.migrated = true
+	return m.migrateErr
+}
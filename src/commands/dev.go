@@ -2,46 +2,61 @@ package commands
 
 import (
 	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/colinxr/shopify-theme-manager/shopify"
 	"github.com/spf13/cobra"
 )
 
-func NewDevCommand(cfg config.Manager) *cobra.Command {
+func NewDevCommand(cfg config.Manager, runner shopify.Runner) *cobra.Command {
+	var (
+		env       string
+		allowLive bool
+	)
+
 	cmd := &cobra.Command{
-		Use:   "dev <theme-id>",
-		Short: "Start theme development server",
-		Args:  cobra.MaximumNArgs(1),
+		Use:               "dev [store-alias] [theme-id|--env <name>]",
+		Short:             "Start theme development server",
+		Args:              cobra.RangeArgs(0, 2),
+		ValidArgsFunction: completeStoreThenTheme(cfg, runner),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var themeID string
-			if len(args) > 0 {
-				themeID = args[0]
+			store, rest, err := storeArg(cfg, args, 1)
+			if err != nil {
+				return err
 			}
 
-			// Build base command args
-			cmdArgs := []string{"theme", "dev"}
-			if themeID != "" {
-				cmdArgs = append(cmdArgs, "--theme", themeID)
+			var themeID string
+			if len(rest) > 0 {
+				themeID = rest[0]
 			}
 
-			// Add port flag if provided
-			if port, _ := cmd.Flags().GetString("port"); port != "" {
-				cmdArgs = append(cmdArgs, "--port", port)
+			themeID, err = resolveThemeID(cmd.Context(), runner, store, themeID, env)
+			if err != nil {
+				return err
 			}
 
-			// Create command with all arguments
-			shopifyCmd := execCommand("shopify", cmdArgs...)
-
-			// Set output to current process
-			shopifyCmd.Stdout = cmd.OutOrStdout()
-			shopifyCmd.Stderr = cmd.ErrOrStderr()
-			shopifyCmd.Stdin = cmd.InOrStdin()
+			// Syncing to an existing theme overwrites it
+			if err := guardLiveTheme(cmd.Context(), runner, store, themeID, allowLive); err != nil {
+				return err
+			}
 
-			return shopifyCmd.Run()
+			opts := shopify.DevOptions{Theme: themeID}
+			opts.Port, _ = cmd.Flags().GetString("port")
+
+			// Run from the store's project directory, attached to the terminal
+			return runner.Run(cmd.Context(), shopify.Invocation{
+				Args:   shopify.DevArgs(store.StoreID, opts),
+				Dir:    store.ProjectPath(cfg.GetWorkspace()),
+				Stdin:  cmd.InOrStdin(),
+				Stdout: cmd.OutOrStdout(),
+				Stderr: cmd.ErrOrStderr(),
+			})
 		},
 	}
 
 	// Add flags
 	cmd.Flags().String("port", "", "Port to use")
 	cmd.Flags().Bool("live-reload", true, "Enable live reload")
+	addThemeEnvFlag(cmd, cfg, &env)
+	addAllowLiveFlag(cmd, &allowLive)
 
 	return cmd
 }
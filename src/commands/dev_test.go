@@ -1,65 +1,83 @@
 package commands
 
 import (
-	"os/exec"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
 )
 
 func TestDevCommand(t *testing.T) {
+	t.Parallel()
+
 	tests := []struct {
 		name     string
 		args     []string
-		wantCmd  string
 		wantArgs []string
 		wantErr  bool
 		errMsg   string
 	}{
 		{
 			name:     "optional theme ID",
-			args:     []string{"dev"},
-			wantCmd:  "shopify",
-			wantArgs: []string{"theme", "dev"},
+			args:     []string{"dev", "test-alias"},
+			wantArgs: []string{"theme", "dev", "--store", "test-store"},
 			wantErr:  false,
 		},
 		{
 			name:     "valid theme ID",
-			args:     []string{"dev", "123456"},
-			wantCmd:  "shopify",
-			wantArgs: []string{"theme", "dev", "--theme", "123456"},
+			args:     []string{"dev", "test-alias", "123456"},
+			wantArgs: []string{"theme", "dev", "--store", "test-store", "--theme", "123456"},
 			wantErr:  false,
 		},
 		{
 			name:    "too many arguments",
-			args:    []string{"dev", "123456", "extra"},
+			args:    []string{"dev", "test-alias", "123456", "extra"},
 			wantErr: true,
-			errMsg:  "accepts at most 1 arg(s), received 2",
+			errMsg:  "accepts between 0 and 2 arg(s), received 3",
+		},
+		{
+			name:    "store not found",
+			args:    []string{"dev", "invalid-store"},
+			wantErr: true,
+			errMsg:  "store with alias \"invalid-store\" not found",
 		},
 		{
 			name:     "theme ID with flags",
-			args:     []string{"dev", "123456", "--port", "9292"},
-			wantCmd:  "shopify",
-			wantArgs: []string{"theme", "dev", "--theme", "123456", "--port", "9292"},
+			args:     []string{"dev", "test-alias", "123456", "--port", "9292"},
+			wantArgs: []string{"theme", "dev", "--store", "test-store", "--theme", "123456", "--port", "9292"},
+			wantErr:  false,
+		},
+		{
+			name:     "named theme environment",
+			args:     []string{"dev", "test-alias", "--env", "staging"},
+			wantArgs: []string{"theme", "dev", "--store", "test-store", "--theme", "777"},
 			wantErr:  false,
 		},
+		{
+			name:    "unknown theme environment",
+			args:    []string{"dev", "test-alias", "-e", "preview"},
+			wantErr: true,
+			errMsg:  "store \"test-alias\" has no theme environment \"preview\"",
+		},
+		{
+			name:    "theme ID and environment",
+			args:    []string{"dev", "test-alias", "123456", "--env", "staging"},
+			wantErr: true,
+			errMsg:  "pass either a theme ID or --env, not both",
+		},
 	}
 
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
 			h := newTestHelper(t)
 
-			var executedCmd string
-			var executedArgs []string
+			h.mock.SetWorkspace("/workspace")
+			h.mock.AddStore("test-store", "test-alias", "test-dir")
+			h.mock.SetThemeEnv("test-alias", "staging", "777")
 
-			cleanup := MockExecCommand(func(cmd string, args ...string) *exec.Cmd {
-				executedCmd = cmd
-				executedArgs = args
-				return exec.Command("true")
-			})
-			defer cleanup()
-
-			cmd := NewDevCommand(h.mock)
+			cmd := NewDevCommand(h.mock, h.runner)
 			h.setupCommand(cmd)
 
 			h.cmd.SetArgs(tt.args)
@@ -71,6 +89,9 @@ func TestDevCommand(t *testing.T) {
 				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
 					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
 				}
+				if calls := h.runner.Calls(); len(calls) != 0 {
+					t.Errorf("shopify ran %d times, want 0", len(calls))
+				}
 				return
 			}
 
@@ -79,33 +100,83 @@ func TestDevCommand(t *testing.T) {
 				return
 			}
 
-			if tt.wantCmd != "" && executedCmd != tt.wantCmd {
-				t.Errorf("executed command = %s, want %s", executedCmd, tt.wantCmd)
+			call := h.runner.LastCall()
+			if call == nil {
+				t.Fatal("shopify was not run")
 			}
 
-			if tt.wantArgs != nil && !reflect.DeepEqual(executedArgs, tt.wantArgs) {
-				t.Errorf("executed args = %v, want %v", executedArgs, tt.wantArgs)
+			if !reflect.DeepEqual(call.Args, tt.wantArgs) {
+				t.Errorf("executed args = %v, want %v", call.Args, tt.wantArgs)
+			}
+
+			if want := "/workspace/test-dir"; call.Dir != want {
+				t.Errorf("working directory = %s, want %s", call.Dir, want)
+			}
+
+			if call.Stdin == nil || call.Stdout != h.output || call.Stderr != h.output {
+				t.Error("dev server is not attached to the command's stdio")
 			}
 		})
 	}
 }
 
+func TestDevCommand_AbsoluteProjectDir(t *testing.T) {
+	t.Parallel()
+	h := newTestHelper(t)
+
+	h.mock.SetWorkspace("/unused/workspace")
+	h.mock.AddStore("test-store", "test-alias", "/projects/test-dir")
+
+	cmd := NewDevCommand(h.mock, h.runner)
+	h.setupCommand(cmd)
+
+	h.cmd.SetArgs([]string{"dev", "test-alias"})
+	if err := h.cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := h.runner.LastCall().Dir; got != "/projects/test-dir" {
+		t.Errorf("working directory = %s, want /projects/test-dir", got)
+	}
+}
+
 func TestDevCommand_ExecutionFailure(t *testing.T) {
+	t.Parallel()
 	h := newTestHelper(t)
 
-	cleanup := MockExecCommand(func(cmd string, args ...string) *exec.Cmd {
-		// Return a command that will fail
-		return exec.Command("false")
-	})
-	defer cleanup()
+	h.mock.AddStore("test-store", "test-alias", ".")
+	h.runner.Err = errors.New("exit status 1")
 
-	cmd := NewDevCommand(h.mock)
+	cmd := NewDevCommand(h.mock, h.runner)
 	h.setupCommand(cmd)
 
-	h.cmd.SetArgs([]string{"dev", "123456"})
+	h.cmd.SetArgs([]string{"dev", "test-alias", "123456"})
 	err := h.cmd.Execute()
 
 	if err == nil {
 		t.Error("expected error from failed command execution but got none")
 	}
 }
+
+func TestDevCommand_LiveTheme(t *testing.T) {
+	t.Parallel()
+	h := newTestHelper(t)
+
+	h.mock.AddStore("test-store", "test-alias", ".")
+	h.runner.Stdout = guardThemeList
+
+	cmd := NewDevCommand(h.mock, h.runner)
+	h.setupCommand(cmd)
+
+	h.cmd.SetArgs([]string{"dev", "test-alias", "1"})
+	err := h.cmd.Execute()
+
+	if err == nil || !strings.Contains(err.Error(), "pass --allow-live") {
+		t.Errorf("error = %v, want live theme refusal", err)
+	}
+	for _, call := range h.runner.Calls() {
+		if call.Args[1] == "dev" {
+			t.Errorf("dev server started against the live theme: %v", call.Args)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/spf13/cobra"
+)
+
+// existingProjectDir returns a store's resolved project directory, failing
+// if it doesn't exist.
+func existingProjectDir(cfg config.Manager, store *config.Store) (string, error) {
+	dir := store.ProjectPath(cfg.GetWorkspace())
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("project directory %s for store %q does not exist", dir, store.Alias)
+	}
+	return dir, nil
+}
+
+// ensureDir checks that dir exists. When it doesn't and stm is running
+// interactively, it offers to create it.
+func ensureDir(cmd *cobra.Command, dir string, interactive bool) error {
+	err := config.CheckDir(dir)
+	if !errors.Is(err, config.ErrDirNotFound) {
+		return err
+	}
+	if !interactive {
+		return fmt.Errorf("%w; create it first", err)
+	}
+
+	ok, promptErr := confirm(fmt.Sprintf("Directory %s does not exist. Create it", dir))
+	if promptErr != nil {
+		return promptErr
+	}
+	if !ok {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Created %s\n", dir)
+	return nil
+}
+
+// checkProjectDir checks the project directory of the store with the given
+// alias before it is saved. The directory must exist; being absolute inside
+// the workspace, outside the workspace or shared with another store only
+// produces warnings.
+func checkProjectDir(cmd *cobra.Command, cfg config.Manager, alias, projectDir string, interactive bool) error {
+	workspace := cfg.GetWorkspace()
+	dir := (&config.Store{ProjectDir: projectDir}).ProjectPath(workspace)
+	if err := ensureDir(cmd, dir, interactive); err != nil {
+		return err
+	}
+
+	stderr := cmd.ErrOrStderr()
+	for _, warning := range config.ProjectDirWarnings(workspace, projectDir) {
+		fmt.Fprintf(stderr, "Warning: %s\n", warning)
+	}
+	for _, store := range config.StoresSharingDir(cfg.ListStores(), workspace, dir, alias) {
+		fmt.Fprintf(stderr, "Warning: store %s uses the same project directory; commands run inside it will need a store alias\n", store.Alias)
+	}
+	return nil
+}
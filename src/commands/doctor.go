@@ -0,0 +1,325 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/colinxr/shopify-theme-manager/shopify"
+	"github.com/spf13/cobra"
+)
+
+// Results of a stm doctor check, from best to worst
+const (
+	checkPass = "pass"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// doctorTimeout bounds each program stm doctor runs to read its version
+const doctorTimeout = 30 * time.Second
+
+// minNodeVersion is the oldest Node.js release the Shopify CLI 3 supports
+var minNodeVersion = shopify.Version{Major: 18, Minor: 20}
+
+// lookPath finds programs on PATH. It is declared at package level for
+// mocking in tests.
+var lookPath = exec.LookPath
+
+// toolVersion runs a program with --version and returns its output. It is
+// declared at package level for mocking in tests.
+var toolVersion = func(ctx context.Context, path string) (string, error) {
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	return string(out), err
+}
+
+// doctorCheck is the outcome of one stm doctor check
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	// Hint suggests how to fix a warning or failure
+	Hint string `json:"hint,omitempty"`
+}
+
+// doctorReport is the rendered form of stm doctor
+type doctorReport struct {
+	Checks   []doctorCheck `json:"checks"`
+	Passed   int           `json:"passed"`
+	Warnings int           `json:"warnings"`
+	Failed   int           `json:"failed"`
+}
+
+func NewDoctorCommand(cfg config.Manager, runner shopify.Runner) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check stm, the Shopify CLI and your setup for problems",
+		Long: `Check that the Shopify CLI and the programs it needs are installed, that
+the config file can be read and saved, that the workspace exists, and that
+every store's project directory exists and looks like a theme.
+
+Each check passes, warns or fails, with a hint on how to fix it. stm exits
+non-zero when a check fails.`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{worksWithoutConfig: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Failed checks are not usage errors
+			cmd.SilenceUsage = true
+			report := runDoctor(cmd.Context(), cfg, runner)
+
+			out := cmd.OutOrStdout()
+			if jsonOutput {
+				if err := renderOutput(out, outputJSON, report, nil); err != nil {
+					return err
+				}
+			} else {
+				if err := printDoctorReport(cmd, report); err != nil {
+					return err
+				}
+			}
+
+			if report.Failed > 0 {
+				return fmt.Errorf("%d of %d checks failed", report.Failed, len(report.Checks))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the report as JSON")
+	return cmd
+}
+
+// runDoctor runs every check in the order they are reported.
+func runDoctor(ctx context.Context, cfg config.Manager, runner shopify.Runner) doctorReport {
+	cli, cliCheck := checkShopifyCLI(ctx, runner)
+	checks := []doctorCheck{cliCheck}
+	checks = append(checks, checkPrerequisites(ctx, cli)...)
+
+	// A config file that can't be read has no workspace or stores to check
+	configCheck, readable := checkConfigFile(cfg.ConfigPath())
+	checks = append(checks, configCheck)
+	if readable {
+		checks = append(checks, checkWorkspace(cfg.GetWorkspace()))
+		checks = append(checks, checkStores(cfg)...)
+	}
+
+	report := doctorReport{Checks: checks}
+	for _, check := range checks {
+		switch check.Status {
+		case checkPass:
+			report.Passed++
+		case checkWarn:
+			report.Warnings++
+		case checkFail:
+			report.Failed++
+		}
+	}
+	return report
+}
+
+// checkShopifyCLI finds the Shopify CLI and its version. The version is nil
+// when it could not be read.
+func checkShopifyCLI(ctx context.Context, runner shopify.Runner) (*shopify.Version, doctorCheck) {
+	check := doctorCheck{Name: "Shopify CLI"}
+	path, err := lookPath(shopify.DefaultBinary)
+	if err != nil {
+		check.Status = checkFail
+		check.Detail = "shopify not found on PATH"
+		check.Hint = "install it with: brew install shopify-cli (or npm install -g @shopify/cli)"
+		return nil, check
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+	version, err := shopify.CLIVersion(ctx, runner)
+	if err != nil {
+		check.Status = checkFail
+		check.Detail = fmt.Sprintf("%s: %v", path, err)
+		check.Hint = "reinstall the Shopify CLI"
+		return nil, check
+	}
+
+	check.Status = checkPass
+	check.Detail = fmt.Sprintf("%s (%s)", version, path)
+	if err := shopify.CheckCLIVersion(version); err != nil {
+		check.Status = checkFail
+		check.Hint = err.Error()
+		return &version, check
+	}
+	return &version, check
+}
+
+// checkPrerequisites checks the runtime the installed Shopify CLI needs:
+// Node.js for version 3, which is also assumed when the CLI is missing,
+// and Ruby for the legacy version 2.
+func checkPrerequisites(ctx context.Context, cli *shopify.Version) []doctorCheck {
+	if cli != nil && cli.Major < 3 {
+		return []doctorCheck{checkTool(ctx, "Ruby", "ruby", shopify.Version{}, "install Ruby, or upgrade to Shopify CLI 3, which doesn't need it")}
+	}
+	return []doctorCheck{checkTool(ctx, "Node.js", "node", minNodeVersion, fmt.Sprintf("install Node.js %d.%d or later from https://nodejs.org", minNodeVersion.Major, minNodeVersion.Minor))}
+}
+
+// checkTool checks that a program is on PATH and at least version min.
+func checkTool(ctx context.Context, name, program string, min shopify.Version, hint string) doctorCheck {
+	check := doctorCheck{Name: name, Status: checkFail, Hint: hint}
+	path, err := lookPath(program)
+	if err != nil {
+		check.Detail = program + " not found on PATH"
+		return check
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+	out, err := toolVersion(ctx, path)
+	if err != nil {
+		check.Detail = fmt.Sprintf("%s --version failed: %v", path, err)
+		return check
+	}
+	version, err := shopify.ParseVersion(out)
+	if err != nil {
+		check.Status = checkWarn
+		check.Detail = fmt.Sprintf("%s: %v", path, err)
+		return check
+	}
+
+	check.Detail = fmt.Sprintf("%s (%s)", version, path)
+	if version.Less(min) {
+		check.Detail = fmt.Sprintf("%s is older than %s", check.Detail, min)
+		return check
+	}
+	check.Status = checkPass
+	check.Hint = ""
+	return check
+}
+
+// checkConfigFile checks the config file and reports whether it could be
+// read.
+func checkConfigFile(path string) (doctorCheck, bool) {
+	check := doctorCheck{Name: "Config file", Status: checkPass, Detail: path}
+	version, err := config.CheckConfigFile(path)
+	if err != nil {
+		check.Status = checkFail
+		check.Detail = err.Error()
+		check.Hint = "fix the file, or restore the last good copy from " + path + ".bak"
+		return check, false
+	}
+	if err := config.CheckWritable(path); err != nil {
+		check.Status = checkFail
+		check.Detail = fmt.Sprintf("%s is not writable: %v", path, err)
+		check.Hint = "check the permissions of " + filepath.Dir(path)
+		return check, true
+	}
+	if version < config.CurrentVersion {
+		check.Status = checkWarn
+		check.Detail = fmt.Sprintf("%s uses schema version %d", path, version)
+		check.Hint = "run: stm config migrate"
+	}
+	return check, true
+}
+
+func checkWorkspace(workspace string) doctorCheck {
+	check := doctorCheck{Name: "Workspace", Status: checkPass, Detail: workspace}
+	if workspace == "" {
+		check.Status = checkWarn
+		check.Detail = "not set"
+		check.Hint = "run: stm set-workspace <dir>"
+		return check
+	}
+	if err := config.CheckDir(workspace); err != nil {
+		check.Status = checkFail
+		check.Detail = err.Error()
+		check.Hint = "create it, or run: stm set-workspace <dir>"
+	}
+	return check
+}
+
+// checkStores checks that aliases are unique and that every store's
+// project directory exists and looks like a theme.
+func checkStores(cfg config.Manager) []doctorCheck {
+	stores := cfg.ListStores()
+	aliases := doctorCheck{Name: "Store aliases", Status: checkPass, Detail: fmt.Sprintf("all %d aliases are unique", len(stores))}
+	if len(stores) == 0 {
+		aliases.Status = checkWarn
+		aliases.Detail = "no stores configured"
+		aliases.Hint = "run: stm add, or find theme projects with: stm scan"
+	}
+
+	counts := make(map[string]int)
+	for _, store := range stores {
+		counts[store.Alias]++
+	}
+	var duplicates []string
+	for _, store := range stores {
+		if counts[store.Alias] > 1 && !containsString(duplicates, store.Alias) {
+			duplicates = append(duplicates, store.Alias)
+		}
+	}
+	if len(duplicates) > 0 {
+		aliases.Status = checkFail
+		aliases.Detail = fmt.Sprintf("used by more than one store: %s", strings.Join(duplicates, ", "))
+		aliases.Hint = "rename the duplicates in " + cfg.ConfigPath()
+	}
+
+	checks := []doctorCheck{aliases}
+	for i := range stores {
+		checks = append(checks, checkStore(cfg, stores, &stores[i]))
+	}
+	return checks
+}
+
+func checkStore(cfg config.Manager, stores []config.Store, store *config.Store) doctorCheck {
+	workspace := cfg.GetWorkspace()
+	check := doctorCheck{Name: "Store " + store.Alias, Status: checkPass}
+
+	dir, err := existingProjectDir(cfg, store)
+	if err != nil {
+		check.Status = checkFail
+		check.Detail = err.Error()
+		check.Hint = fmt.Sprintf("create it, or change it with: stm edit %s", store.Alias)
+		return check
+	}
+	check.Detail = dir
+
+	var problems, hints []string
+	if !shopify.IsThemeDir(dir) {
+		problems = append(problems, fmt.Sprintf("%s doesn't look like a theme", dir))
+		hints = append(hints, fmt.Sprintf("download the theme with: stm pull %s", store.Alias))
+	}
+	if warnings := config.ProjectDirWarnings(workspace, store.ProjectDir); len(warnings) > 0 {
+		problems = append(problems, warnings...)
+		hints = append(hints, fmt.Sprintf("change it with: stm edit %s", store.Alias))
+	}
+	for _, other := range config.StoresSharingDir(stores, workspace, dir, store.Alias) {
+		problems = append(problems, fmt.Sprintf("shares its project directory with store %s", other.Alias))
+	}
+	if len(problems) > 0 {
+		check.Status = checkWarn
+		check.Detail = strings.Join(problems, "; ")
+		check.Hint = strings.Join(hints, "; ")
+	}
+	return check
+}
+
+func printDoctorReport(cmd *cobra.Command, report doctorReport) error {
+	out := cmd.OutOrStdout()
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, check := range report.Checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", strings.ToUpper(check.Status), check.Name, check.Detail)
+		if check.Hint != "" {
+			fmt.Fprintf(tw, "\t\thint: %s\n", check.Hint)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\n%d passed, %d warnings, %d failed\n", report.Passed, report.Warnings, report.Failed)
+	return nil
+}
@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+)
+
+// setupDoctor configures a healthy setup: a current config file, a
+// workspace and store acme whose project directory holds a theme.
+func setupDoctor(t *testing.T, h *testHelper) string {
+	t.Helper()
+	root := t.TempDir()
+	configPath := filepath.Join(root, "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"version": 6, "stores": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h.mock.(*MockConfig).configPath = configPath
+
+	workspace := filepath.Join(root, "workspace")
+	if err := os.MkdirAll(filepath.Join(workspace, "acme", "layout"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workspace, "acme", "layout", "theme.liquid"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	h.mock.SetWorkspace(workspace)
+	h.mock.AddStore("acme.myshopify.com", "acme", "acme")
+	h.runner.Stdout = "3.58.2\n"
+	return workspace
+}
+
+func TestDoctorCommand(t *testing.T) {
+	healthyTools := map[string]string{"shopify": "", "node": "v20.11.1\n"}
+
+	tests := []struct {
+		name     string
+		tools    map[string]string
+		setup    func(t *testing.T, h *testHelper, workspace string)
+		wantErr  bool
+		contains []string
+	}{
+		{
+			name:     "healthy",
+			tools:    healthyTools,
+			contains: []string{"PASS  Shopify CLI", "3.58.2 (/usr/local/bin/shopify)", "PASS  Node.js", "20.11.1", "PASS  Store acme", "6 passed, 0 warnings, 0 failed"},
+		},
+		{
+			name:     "shopify missing",
+			tools:    map[string]string{"node": "v20.11.1"},
+			wantErr:  true,
+			contains: []string{"FAIL  Shopify CLI", "shopify not found on PATH", "hint: install it with: brew install shopify-cli"},
+		},
+		{
+			name:     "node too old",
+			tools:    map[string]string{"shopify": "", "node": "v16.20.2"},
+			wantErr:  true,
+			contains: []string{"FAIL  Node.js", "16.20.2 (/usr/local/bin/node) is older than 18.20.0", "hint: install Node.js 18.20 or later"},
+		},
+		{
+			name:  "legacy CLI checks ruby",
+			tools: map[string]string{"shopify": "", "ruby": "ruby 2.7.8p225"},
+			setup: func(t *testing.T, h *testHelper, workspace string) {
+				h.runner.Stdout = "2.15.6\n"
+			},
+			wantErr:  true,
+			contains: []string{"FAIL  Shopify CLI", "stm needs 3.0.0 or later", "PASS  Ruby", "2.7.8"},
+		},
+		{
+			name:  "CLI major version stm doesn't know",
+			tools: healthyTools,
+			setup: func(t *testing.T, h *testHelper, workspace string) {
+				h.runner.Stdout = "4.0.0\n"
+			},
+			wantErr:  true,
+			contains: []string{"FAIL  Shopify CLI", "stm knows up to 3.x", "STM_SKIP_CLI_CHECK=1"},
+		},
+		{
+			name:  "workspace not set",
+			tools: healthyTools,
+			setup: func(t *testing.T, h *testHelper, workspace string) {
+				h.mock.(*MockConfig).workspace = ""
+				h.mock.UpdateStore("acme", config.Store{StoreID: "acme.myshopify.com", Alias: "acme", ProjectDir: filepath.Join(workspace, "acme")})
+			},
+			contains: []string{"WARN  Workspace", "not set", "hint: run: stm set-workspace <dir>"},
+		},
+		{
+			name:  "old config schema",
+			tools: healthyTools,
+			setup: func(t *testing.T, h *testHelper, workspace string) {
+				os.WriteFile(h.mock.ConfigPath(), []byte(`{"version": 2, "stores": []}`), 0644)
+			},
+			contains: []string{"WARN  Config file", "uses schema version 2", "hint: run: stm config migrate"},
+		},
+		{
+			name:  "unreadable config",
+			tools: healthyTools,
+			setup: func(t *testing.T, h *testHelper, workspace string) {
+				os.WriteFile(h.mock.ConfigPath(), []byte(`{"stores": [`), 0644)
+			},
+			wantErr:  true,
+			contains: []string{"FAIL  Config file", "failed to read config", ".bak"},
+		},
+		{
+			name:  "store problems",
+			tools: healthyTools,
+			setup: func(t *testing.T, h *testHelper, workspace string) {
+				os.Mkdir(filepath.Join(workspace, "empty"), 0755)
+				h.mock.AddStore("empty.myshopify.com", "empty", "empty")
+				h.mock.AddStore("gone.myshopify.com", "gone", "gone")
+				h.mock.AddStore("acme-eu.myshopify.com", "acme-eu", "acme")
+			},
+			wantErr: true,
+			contains: []string{
+				"WARN  Store empty", "doesn't look like a theme", "hint: download the theme with: stm pull empty",
+				"FAIL  Store gone", "does not exist", "hint: create it, or change it with: stm edit gone",
+				"WARN  Store acme-eu", "shares its project directory with store acme",
+			},
+		},
+		{
+			name:  "duplicate aliases",
+			tools: healthyTools,
+			setup: func(t *testing.T, h *testHelper, workspace string) {
+				mock := h.mock.(*MockConfig)
+				mock.stores = append(mock.stores, config.Store{StoreID: "other.myshopify.com", Alias: "acme", ProjectDir: "acme"})
+			},
+			wantErr:  true,
+			contains: []string{"FAIL  Store aliases", "used by more than one store: acme"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer MockTools(tt.tools)()
+			h := newTestHelper(t)
+			workspace := setupDoctor(t, h)
+			if tt.setup != nil {
+				tt.setup(t, h, workspace)
+			}
+
+			h.setupCommand(NewDoctorCommand(h.mock, h.runner))
+			h.cmd.SetArgs([]string{"doctor"})
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "checks failed") {
+					t.Errorf("error = %v, want failed checks", err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			output := h.output.String()
+			for _, want := range tt.contains {
+				if !strings.Contains(output, want) {
+					t.Errorf("output missing %q:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
+
+func TestDoctorCommand_JSON(t *testing.T) {
+	defer MockTools(map[string]string{"shopify": "", "node": "v20.11.1"})()
+	h := newTestHelper(t)
+	setupDoctor(t, h)
+	h.mock.(*MockConfig).workspace = ""
+	out := &strings.Builder{}
+	h.cmd.SetOut(out)
+
+	h.setupCommand(NewDoctorCommand(h.mock, h.runner))
+	h.cmd.SetArgs([]string{"doctor", "--json"})
+	if err := h.cmd.Execute(); err == nil {
+		t.Fatal("expected the missing project directory to fail")
+	}
+
+	var report doctorReport
+	if err := json.Unmarshal([]byte(out.String()), &report); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+	statuses := make(map[string]string)
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	want := map[string]string{
+		"Shopify CLI":   checkPass,
+		"Node.js":       checkPass,
+		"Config file":   checkPass,
+		"Workspace":     checkWarn,
+		"Store aliases": checkPass,
+		"Store acme":    checkFail,
+	}
+	for name, status := range want {
+		if statuses[name] != status {
+			t.Errorf("check %q status = %q, want %q", name, statuses[name], status)
+		}
+	}
+	if report.Passed != 4 || report.Warnings != 1 || report.Failed != 1 {
+		t.Errorf("summary = %d passed, %d warnings, %d failed; want 4, 1, 1", report.Passed, report.Warnings, report.Failed)
+	}
+}
@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/colinxr/shopify-theme-manager/shopify"
+	"github.com/spf13/cobra"
+)
+
+// defaultParallel is the number of stores stm each works on at once
+const defaultParallel = 4
+
+// eachResult is the outcome of running the Shopify CLI for one store
+type eachResult struct {
+	alias    string
+	err      error
+	duration time.Duration
+}
+
+func NewEachCommand(cfg config.Manager, runner shopify.Runner) *cobra.Command {
+	var (
+		tags     []string
+		parallel int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "each [--tag <expr>] [--parallel N] -- <shopify args>...",
+		Short: "Run a Shopify CLI command for many stores",
+		Long: `Run a Shopify CLI command for every configured store, or for the stores
+matching --tag. Each run happens in the store's project directory with
+--store set, and its output lines are prefixed with the store alias.
+
+Failures don't stop the other stores. A summary is printed at the end and
+stm exits non-zero if any store failed.
+
+"theme push" and "theme dev" never run against a store's live theme: the
+theme given with --theme is checked for every store, --allow-live and
+--live are refused, and protected stores must name a theme to push to.
+
+--store and --environment are refused for every command, since stm each
+sets the store and an environment from shopify.theme.toml can override it
+and the theme.`,
+		Example: `  stm each -- theme check
+  stm each --tag 'base:dawn & !tier:plus' --parallel 8 -- theme push --theme 123 --nodelete`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if parallel < 1 {
+				return fmt.Errorf("--parallel must be at least 1")
+			}
+			for _, arg := range args {
+				flag := strings.SplitN(arg, "=", 2)[0]
+				if flag == "--store" || flag == "-s" {
+					return fmt.Errorf("don't pass --store; stm each adds it for every store")
+				}
+				if flag == "--environment" || flag == "-e" {
+					return fmt.Errorf("don't pass %s; an environment can set the store and theme, which stm each checks for every store", flag)
+				}
+				if writesTheme(args) && containsString(liveFlags, flag) {
+					return fmt.Errorf("stm each doesn't write to live themes; drop %s, or run stm push <store-alias> --allow-live for one store", arg)
+				}
+			}
+
+			stores, err := selectStores(cfg.ListStores(), tags)
+			if err != nil {
+				return err
+			}
+			if len(stores) == 0 {
+				return fmt.Errorf("no stores match")
+			}
+
+			results := runEach(cmd, cfg, runner, stores, args, parallel)
+			return printEachSummary(cmd, results)
+		},
+	}
+
+	addTagFlag(cmd, cfg, &tags)
+	cmd.Flags().IntVarP(&parallel, "parallel", "p", defaultParallel, "Number of stores to run at once")
+	return cmd
+}
+
+// runEach runs the Shopify CLI for each store on a pool of parallel
+// workers. Results are returned in the order of stores.
+func runEach(cmd *cobra.Command, cfg config.Manager, runner shopify.Runner, stores []config.Store, args []string, parallel int) []eachResult {
+	results := make([]eachResult, len(stores))
+	jobs := make(chan int)
+	width := aliasWidth(stores)
+	workspace := cfg.GetWorkspace()
+	stdout, stderr := cmd.OutOrStdout(), cmd.ErrOrStderr()
+	write, theme := writesTheme(args), themeFlag(args)
+
+	// outMu keeps lines from different stores whole on the shared output
+	var outMu sync.Mutex
+	var wg sync.WaitGroup
+
+	worker := func() {
+		defer wg.Done()
+		for i := range jobs {
+			store := stores[i]
+			prefix := fmt.Sprintf("%-*s | ", width, store.Alias)
+			out := newPrefixWriter(stdout, &outMu, prefix)
+			errOut := newPrefixWriter(stderr, &outMu, prefix)
+
+			start := time.Now()
+			if write {
+				if err := guardEachStore(cmd.Context(), runner, &store, args[1], theme); err != nil {
+					results[i] = eachResult{alias: store.Alias, err: err, duration: time.Since(start)}
+					continue
+				}
+			}
+			err := runner.Run(cmd.Context(), shopify.Invocation{
+				Args:   append(append([]string(nil), args...), "--store", store.StoreID),
+				Dir:    store.ProjectPath(workspace),
+				Stdout: out,
+				Stderr: errOut,
+			})
+			out.Flush()
+			errOut.Flush()
+
+			results[i] = eachResult{alias: store.Alias, err: err, duration: time.Since(start)}
+		}
+	}
+
+	if parallel > len(stores) {
+		parallel = len(stores)
+	}
+	wg.Add(parallel)
+	for n := 0; n < parallel; n++ {
+		go worker()
+	}
+	for i := range stores {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// liveFlags let the Shopify CLI write to a live theme without stm's
+// confirmation. "-a" and "-l" are the short forms of "--allow-live" and
+// "--live".
+var liveFlags = []string{"--allow-live", "-a", "--live", "-l"}
+
+// writesTheme reports whether args run a Shopify CLI command that writes
+// to a theme.
+func writesTheme(args []string) bool {
+	return len(args) >= 2 && args[0] == "theme" && (args[1] == "push" || args[1] == "dev")
+}
+
+// themeFlag returns the value of --theme or -t in args, or "" if there is
+// none.
+func themeFlag(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		for _, flag := range []string{"--theme", "-t"} {
+			if arg == flag && i+1 < len(args) {
+				return args[i+1]
+			}
+			if value, ok := strings.CutPrefix(arg, flag+"="); ok {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// guardEachStore checks that "theme push" or "theme dev" run by stm each
+// doesn't write to a store's live theme. Unlike guardLiveTheme it never
+// asks: live themes are always refused, and a push to a protected store
+// must name its theme so it can be checked. theme is an ID or a name, as
+// the Shopify CLI accepts both.
+func guardEachStore(ctx context.Context, runner shopify.Runner, store *config.Store, command, theme string) error {
+	if theme == "" {
+		if command == "push" && store.Protected {
+			return fmt.Errorf("store %q is protected; pass --theme so stm can check it isn't the live theme", store.Alias)
+		}
+		return nil
+	}
+
+	themes, err := shopify.ListThemes(ctx, runner, store.StoreID, shopify.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("checking the role of theme %s: %w", theme, err)
+	}
+	for _, t := range themes {
+		if t.Role != shopify.RoleLive || (strconv.FormatInt(t.ID, 10) != theme && !strings.EqualFold(t.Name, theme)) {
+			continue
+		}
+		if store.Protected {
+			return fmt.Errorf("theme %s is the live theme of protected store %q; writing to it is not allowed", theme, store.Alias)
+		}
+		return fmt.Errorf("theme %s is the live theme of store %q; run stm %s for that store alone with --allow-live", theme, store.Alias, command)
+	}
+	return nil
+}
+
+func aliasWidth(stores []config.Store) int {
+	width := 0
+	for _, store := range stores {
+		if len(store.Alias) > width {
+			width = len(store.Alias)
+		}
+	}
+	return width
+}
+
+// printEachSummary writes a table of per-store results and returns an
+// error if any store failed.
+func printEachSummary(cmd *cobra.Command, results []eachResult) error {
+	failed := 0
+	out := cmd.OutOrStdout()
+	fmt.Fprintln(out)
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STORE\tSTATUS\tDURATION\tERROR")
+	for _, result := range results {
+		status, msg := "ok", ""
+		if result.err != nil {
+			failed++
+			status, msg = "failed", result.err.Error()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.alias, status, result.duration.Round(100*time.Millisecond), msg)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d stores failed", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,344 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/colinxr/shopify-theme-manager/shopify"
+)
+
+func TestEachCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		failStores []string
+		wantStores []string
+		wantErr    bool
+		errMsg     string
+	}{
+		{
+			name:       "all stores",
+			args:       []string{"each", "--", "theme", "check"},
+			wantStores: []string{"alpha.myshopify.com", "beta.myshopify.com", "gamma.myshopify.com"},
+		},
+		{
+			name:       "stores with tag",
+			args:       []string{"each", "--tag", "base:dawn", "--", "theme", "check"},
+			wantStores: []string{"alpha.myshopify.com", "gamma.myshopify.com"},
+		},
+		{
+			name:       "stores with every tag",
+			args:       []string{"each", "--tag", "base:dawn", "--tag", "tier:plus", "--", "theme", "check"},
+			wantStores: []string{"gamma.myshopify.com"},
+		},
+		{
+			name:       "stores matching expression",
+			args:       []string{"each", "--tag", "base:dawn & !tier:plus | !base:dawn", "--", "theme", "check"},
+			wantStores: []string{"alpha.myshopify.com", "beta.myshopify.com"},
+		},
+		{
+			name:    "invalid tag expression",
+			args:    []string{"each", "--tag", "(base:dawn", "--", "theme", "check"},
+			wantErr: true,
+			errMsg:  "missing closing parenthesis",
+		},
+		{
+			name:    "no matching stores",
+			args:    []string{"each", "--tag", "client:none", "--", "theme", "check"},
+			wantErr: true,
+			errMsg:  "no stores match",
+		},
+		{
+			name:       "failures continue",
+			args:       []string{"each", "--parallel", "1", "--", "theme", "check"},
+			failStores: []string{"alpha.myshopify.com"},
+			wantStores: []string{"alpha.myshopify.com", "beta.myshopify.com", "gamma.myshopify.com"},
+			wantErr:    true,
+			errMsg:     "1 of 3 stores failed",
+		},
+		{
+			name:    "store flag",
+			args:    []string{"each", "--", "theme", "push", "--store", "x"},
+			wantErr: true,
+			errMsg:  "don't pass --store",
+		},
+		{
+			name:    "store flag with value",
+			args:    []string{"each", "--", "theme", "pull", "--store=x"},
+			wantErr: true,
+			errMsg:  "don't pass --store",
+		},
+		{
+			name:    "environment flag",
+			args:    []string{"each", "--", "theme", "pull", "--environment", "production"},
+			wantErr: true,
+			errMsg:  "don't pass --environment",
+		},
+		{
+			name:    "short environment flag",
+			args:    []string{"each", "--", "theme", "check", "-e", "production"},
+			wantErr: true,
+			errMsg:  "don't pass -e",
+		},
+		{
+			name:    "invalid parallel",
+			args:    []string{"each", "--parallel", "0", "--", "theme", "check"},
+			wantErr: true,
+			errMsg:  "--parallel must be at least 1",
+		},
+		{
+			name:    "missing shopify args",
+			args:    []string{"each"},
+			wantErr: true,
+			errMsg:  "requires at least 1 arg(s), only received 0",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newTestHelper(t)
+			h.mock.SetWorkspace("/workspace")
+			addTaggedStore(h, "alpha", "base:dawn")
+			addTaggedStore(h, "beta")
+			addTaggedStore(h, "gamma", "base:dawn", "tier:plus")
+
+			h.runner.Respond = func(inv shopify.Invocation) (string, error) {
+				storeID := inv.Args[len(inv.Args)-1]
+				for _, failed := range tt.failStores {
+					if storeID == failed {
+						return "checking\n", errors.New("exit status 1")
+					}
+				}
+				return "checking\nno offenses", nil
+			}
+
+			h.setupCommand(NewEachCommand(h.mock, h.runner))
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var gotStores []string
+			for _, call := range h.runner.Calls() {
+				if !reflect.DeepEqual(call.Args[:2], []string{"theme", "check"}) {
+					t.Errorf("shopify args = %v, want theme check first", call.Args)
+				}
+				gotStores = append(gotStores, call.Args[len(call.Args)-1])
+				if want := "/workspace/" + strings.SplitN(call.Args[len(call.Args)-1], ".", 2)[0]; call.Dir != want {
+					t.Errorf("working directory = %s, want %s", call.Dir, want)
+				}
+			}
+			sort.Strings(gotStores)
+			if !reflect.DeepEqual(gotStores, tt.wantStores) {
+				t.Errorf("ran for stores %v, want %v", gotStores, tt.wantStores)
+			}
+
+			output := h.output.String()
+			for _, store := range tt.wantStores {
+				alias := strings.SplitN(store, ".", 2)[0]
+				if !hasPrefixedLine(output, alias, "checking") {
+					t.Errorf("output has no prefixed lines for %s:\n%s", alias, output)
+				}
+			}
+			for _, store := range tt.failStores {
+				alias := strings.SplitN(store, ".", 2)[0]
+				if !strings.Contains(output, alias+"  failed  ") {
+					t.Errorf("summary does not report %s as failed:\n%s", alias, output)
+				}
+			}
+		})
+	}
+}
+
+func TestEachCommand_Parallel(t *testing.T) {
+	t.Parallel()
+	h := newTestHelper(t)
+	for i := 0; i < 6; i++ {
+		addTaggedStore(h, fmt.Sprintf("store%d", i))
+	}
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	h.runner.Respond = func(inv shopify.Invocation) (string, error) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return "", nil
+	}
+
+	h.setupCommand(NewEachCommand(h.mock, h.runner))
+	h.cmd.SetArgs([]string{"each", "--parallel", "2", "--", "theme", "check"})
+	if err := h.cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls := len(h.runner.Calls()); calls != 6 {
+		t.Errorf("shopify ran %d times, want 6", calls)
+	}
+	if maxRunning != 2 {
+		t.Errorf("at most %d stores ran at once, want 2", maxRunning)
+	}
+}
+
+func TestEachCommand_LiveThemes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantStores []string
+		errMsg     string
+		output     []string
+	}{
+		{
+			name:       "unpublished theme",
+			args:       []string{"theme", "push", "--theme", "2"},
+			wantStores: []string{"alpha.myshopify.com", "beta.myshopify.com"},
+		},
+		{
+			name:   "live theme",
+			args:   []string{"theme", "push", "--theme", "1", "--nodelete"},
+			errMsg: "2 of 2 stores failed",
+			output: []string{`theme 1 is the live theme of store "alpha"`, `live theme of protected store "beta"`},
+		},
+		{
+			name:   "live theme by name",
+			args:   []string{"theme", "dev", "--theme=dawn"},
+			errMsg: "2 of 2 stores failed",
+			output: []string{`theme dawn is the live theme of store "alpha"`},
+		},
+		{
+			name:       "no theme",
+			args:       []string{"theme", "push"},
+			wantStores: []string{"alpha.myshopify.com"},
+			errMsg:     "1 of 2 stores failed",
+			output:     []string{`store "beta" is protected; pass --theme`},
+		},
+		{
+			name:   "allow live",
+			args:   []string{"theme", "push", "--theme", "2", "--allow-live"},
+			errMsg: "stm each doesn't write to live themes; drop --allow-live",
+		},
+		{
+			name:   "live flag",
+			args:   []string{"theme", "push", "-l"},
+			errMsg: "drop -l",
+		},
+		{
+			name:   "short allow live flag",
+			args:   []string{"theme", "dev", "--theme", "2", "-a"},
+			errMsg: "drop -a",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newTestHelper(t)
+			addTaggedStore(h, "alpha")
+			addTaggedStore(h, "beta")
+			store := *h.mock.GetStore("beta")
+			store.Protected = true
+			h.mock.UpdateStore("beta", store)
+
+			h.runner.Respond = func(inv shopify.Invocation) (string, error) {
+				if inv.Args[1] == "list" {
+					return guardThemeList, nil
+				}
+				return "", nil
+			}
+
+			h.setupCommand(NewEachCommand(h.mock, h.runner))
+			h.cmd.SetArgs(append([]string{"each", "--"}, tt.args...))
+			err := h.cmd.Execute()
+
+			if tt.errMsg == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.errMsg != "" && (err == nil || !strings.Contains(err.Error(), tt.errMsg)) {
+				t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+			}
+
+			var gotStores []string
+			for _, call := range h.runner.Calls() {
+				if call.Args[1] != "list" {
+					gotStores = append(gotStores, call.Args[len(call.Args)-1])
+				}
+			}
+			sort.Strings(gotStores)
+			if !reflect.DeepEqual(gotStores, tt.wantStores) {
+				t.Errorf("ran for stores %v, want %v", gotStores, tt.wantStores)
+			}
+			for _, want := range tt.output {
+				if !strings.Contains(h.output.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, h.output.String())
+				}
+			}
+		})
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out strings.Builder
+	var mu sync.Mutex
+	w := newPrefixWriter(&out, &mu, "a | ")
+
+	fmt.Fprint(w, "one\ntw")
+	fmt.Fprint(w, "o\n")
+	fmt.Fprint(w, "three")
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "a | one\na | two\na | three\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+// hasPrefixedLine reports whether output has the line text prefixed with
+// the store alias
+func hasPrefixedLine(output, alias, text string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, " | ", 2)
+		if len(fields) == 2 && strings.TrimSpace(fields[0]) == alias && fields[1] == text {
+			return true
+		}
+	}
+	return false
+}
+
+// addTaggedStore adds a store named alias.myshopify.com in directory alias
+func addTaggedStore(h *testHelper, alias string, tags ...string) {
+	h.mock.AddStore(alias+".myshopify.com", alias, alias)
+	if len(tags) > 0 {
+		h.mock.AddTags(alias, tags...)
+	}
+}
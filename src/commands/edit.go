@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+func NewEditCommand(cfg config.Manager) *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:               "edit [store-alias]",
+		Short:             "Edit a Shopify store configuration",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeStoreAliases(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, _, err := fixedStoreArg(cfg, args, 0)
+			if err != nil {
+				return err
+			}
+			alias := store.Alias
+
+			// Store ID prompt, defaulting to the current value
+			storePrompt := promptui.Prompt{
+				Label:     "Enter the Shopify store ID",
+				Default:   store.StoreID,
+				AllowEdit: true,
+				Validate: func(input string) error {
+					_, err := config.NormalizeStoreID(input)
+					return err
+				},
+			}
+			storeID, err := runPrompt(storePrompt)
+			if err != nil {
+				return err
+			}
+
+			// Alias prompt
+			aliasPrompt := promptui.Prompt{
+				Label:     "Enter an alias for the store",
+				Default:   store.Alias,
+				AllowEdit: true,
+				Validate:  config.ValidateAlias,
+			}
+			newAlias, err := runPrompt(aliasPrompt)
+			if err != nil {
+				return err
+			}
+
+			// Project directory prompt
+			dirPrompt := promptui.Prompt{
+				Label:     "Enter the project directory path",
+				Default:   store.ProjectDir,
+				AllowEdit: true,
+				Validate:  notEmptyValidator,
+			}
+			projectDir, err := runPrompt(dirPrompt)
+			if err != nil {
+				return err
+			}
+			if projectDir != store.ProjectDir {
+				if err := checkProjectDir(cmd, cfg, alias, projectDir, true); err != nil {
+					return err
+				}
+			}
+
+			if !yes {
+				ok, err := confirm(fmt.Sprintf("Save changes to store %s", alias))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					fmt.Fprintln(cmd.OutOrStdout(), "Aborted")
+					return nil
+				}
+			}
+
+			updated := *store
+			updated.StoreID = storeID
+			updated.Alias = newAlias
+			updated.ProjectDir = projectDir
+			if err := cfg.UpdateStore(alias, updated); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Store %s updated successfully\n", newAlias)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation prompt")
+	return cmd
+}
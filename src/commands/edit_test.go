@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/manifoldco/promptui"
+)
+
+func TestEditCommand(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		current         string
+		promptResponses map[string]string
+		mockErrors      map[string]error
+		wantErr         bool
+		errMsg          string
+		verify          func(t *testing.T, h *testHelper)
+	}{
+		{
+			name: "keep current values",
+			args: []string{"edit", "test-alias"},
+			verify: func(t *testing.T, h *testHelper) {
+				store := h.mock.GetStore("test-alias")
+				if store == nil {
+					t.Fatal("store was removed")
+				}
+				if store.StoreID != "test-store" || store.ProjectDir != "test-dir" {
+					t.Errorf("store = %+v, want unchanged", store)
+				}
+			},
+		},
+		{
+			name: "update all values",
+			args: []string{"edit", "test-alias"},
+			promptResponses: map[string]string{
+				"Enter the Shopify store ID":       "new-store",
+				"Enter an alias for the store":     "new-alias",
+				"Enter the project directory path": "new-dir",
+			},
+			verify: func(t *testing.T, h *testHelper) {
+				if h.mock.GetStore("test-alias") != nil {
+					t.Error("old alias still present")
+				}
+				store := h.mock.GetStore("new-alias")
+				if store == nil {
+					t.Fatal("store was not updated")
+				}
+				if store.StoreID != "new-store" {
+					t.Errorf("store ID = %s, want %s", store.StoreID, "new-store")
+				}
+				if store.ProjectDir != "new-dir" {
+					t.Errorf("project dir = %s, want %s", store.ProjectDir, "new-dir")
+				}
+				if !strings.Contains(h.output.String(), "Created ") {
+					t.Errorf("output = %q, want the new project directory created", h.output.String())
+				}
+			},
+		},
+		{
+			name: "confirmation declined",
+			args: []string{"edit", "test-alias"},
+			promptResponses: map[string]string{
+				"Enter the Shopify store ID": "new-store",
+			},
+			mockErrors: map[string]error{
+				"Save changes to store test-alias": promptui.ErrAbort,
+			},
+			verify: func(t *testing.T, h *testHelper) {
+				if store := h.mock.GetStore("test-alias"); store == nil || store.StoreID != "test-store" {
+					t.Errorf("store = %+v, want unchanged", store)
+				}
+			},
+		},
+		{
+			name: "empty store ID",
+			args: []string{"edit", "test-alias"},
+			promptResponses: map[string]string{
+				"Enter the Shopify store ID": "",
+			},
+			wantErr: true,
+			errMsg:  "store ID cannot be empty",
+		},
+		{
+			name: "error on directory prompt",
+			args: []string{"edit", "test-alias"},
+			mockErrors: map[string]error{
+				"Enter the project directory path": errors.New("directory prompt failed"),
+			},
+			wantErr: true,
+			errMsg:  "directory prompt failed",
+		},
+		{
+			name: "alias already in use",
+			args: []string{"edit", "test-alias", "--yes"},
+			promptResponses: map[string]string{
+				"Enter an alias for the store": "other-alias",
+			},
+			wantErr: true,
+			errMsg:  "store with alias \"other-alias\" already exists",
+		},
+		{
+			name:    "current store",
+			args:    []string{"edit", "--yes"},
+			current: "other-alias",
+			promptResponses: map[string]string{
+				"Enter the Shopify store ID": "new-store",
+			},
+			verify: func(t *testing.T, h *testHelper) {
+				if store := h.mock.GetStore("other-alias"); store == nil || store.StoreID != "new-store" {
+					t.Errorf("store = %+v, want the current store updated", store)
+				}
+			},
+		},
+		{
+			name:    "store not found",
+			args:    []string{"edit", "invalid-store"},
+			wantErr: true,
+			errMsg:  "store with alias \"invalid-store\" not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer MockStoreEnv("")()
+			defer MockWorkingDir("/elsewhere")()
+			h := newTestHelper(t)
+			h.mock.SetWorkspace(t.TempDir())
+			h.mock.AddStore("test-store", "test-alias", "test-dir")
+			h.mock.AddStore("other-store", "other-alias", "other-dir")
+			h.mock.SetCurrentStore(tt.current)
+
+			// Unlisted prompts accept their default value
+			cleanup := MockPrompt(func(p promptui.Prompt) (string, error) {
+				label := p.Label.(string)
+				if err, ok := tt.mockErrors[label]; ok {
+					return "", err
+				}
+				if p.IsConfirm {
+					return "y", nil
+				}
+				response, ok := tt.promptResponses[label]
+				if !ok {
+					response = p.Default
+				}
+				if p.Validate != nil {
+					if err := p.Validate(response); err != nil {
+						return "", err
+					}
+				}
+				return response, nil
+			})
+			defer cleanup()
+
+			cmd := NewEditCommand(h.mock)
+			h.setupCommand(cmd)
+
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if tt.verify != nil {
+				tt.verify(t, h)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/colinxr/shopify-theme-manager/shopify"
+	"github.com/spf13/cobra"
+)
+
+func NewEnvCommand(cfg config.Manager) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Sync a store's theme environments with shopify.theme.toml",
+		Long: `Sync a store's theme environments with the shopify.theme.toml in its
+project directory, so the Shopify CLI's --environment flag and stm agree.
+
+Each theme environment of the store becomes an [environments.<name>] table
+with its store, theme and the store's ignore patterns. A live theme is
+written as live = true.`,
+	}
+
+	cmd.AddCommand(
+		newEnvExportCommand(cfg),
+		newEnvImportCommand(cfg),
+	)
+	return cmd
+}
+
+func newEnvExportCommand(cfg config.Manager) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "export [store-alias]",
+		Short: "Write a store's theme environments to shopify.theme.toml",
+		Long: `Write a store's theme environments to the shopify.theme.toml in its
+project directory. Existing environments are updated in place: comments and
+keys stm doesn't manage, such as password, are kept, as are environments stm
+doesn't know. A store without theme environments is exported as an
+environment named after its alias.
+
+The live theme of a protected store is not exported.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeStoreAliases(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, _, err := storeArg(cfg, args, 0)
+			if err != nil {
+				return err
+			}
+			dir, err := existingProjectDir(cfg, store)
+			if err != nil {
+				return err
+			}
+
+			doc, err := shopify.LoadThemeConfigDoc(dir)
+			if err != nil {
+				return err
+			}
+			exported := 0
+			for _, env := range storeEnvironments(store) {
+				if env.Live && store.Protected {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Skipped environment %s: it targets the live theme of protected store %s\n", env.Name, store.Alias)
+					continue
+				}
+				doc.SetEnvironment(env)
+				exported++
+			}
+
+			if dryRun {
+				_, err := cmd.OutOrStdout().Write(doc.Bytes())
+				return err
+			}
+			if err := doc.Write(dir); err != nil {
+				return fmt.Errorf("failed to write %s: %w", shopify.ThemeConfigFile, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Exported %d environments of store %s to %s\n", exported, store.Alias, filepath.Join(dir, shopify.ThemeConfigFile))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the resulting file instead of writing it")
+	return cmd
+}
+
+func newEnvImportCommand(cfg config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import [store-alias]",
+		Short: "Read theme environments from shopify.theme.toml",
+		Long: `Read the environments of the shopify.theme.toml in a store's project
+directory into stm. Each environment's theme becomes a theme environment of
+the store and its ignore patterns are added to the store's default ignore
+patterns. Environments for other stores are skipped.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeStoreAliases(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, _, err := storeArg(cfg, args, 0)
+			if err != nil {
+				return err
+			}
+			dir, err := existingProjectDir(cfg, store)
+			if err != nil {
+				return err
+			}
+
+			envs, err := shopify.ReadThemeConfig(dir)
+			if err != nil {
+				return err
+			}
+			if len(envs) == 0 {
+				return fmt.Errorf("no environments in %s", filepath.Join(dir, shopify.ThemeConfigFile))
+			}
+
+			storeID, err := config.NormalizeStoreID(store.StoreID)
+			if err != nil {
+				return err
+			}
+			stderr := cmd.ErrOrStderr()
+			themes := 0
+			var patterns []string
+			for _, env := range envs {
+				if env.Store != "" {
+					if envStore, err := config.NormalizeStoreID(env.Store); err != nil || envStore != storeID {
+						fmt.Fprintf(stderr, "Skipped environment %s: it is for store %s\n", env.Name, env.Store)
+						continue
+					}
+				}
+
+				themeID := env.Theme
+				if env.Live {
+					themeID = config.LiveTheme
+				}
+				if themeID != "" {
+					if err := cfg.SetThemeEnv(store.Alias, env.Name, themeID); err != nil {
+						fmt.Fprintf(stderr, "Skipped environment %s: %v\n", env.Name, err)
+						continue
+					}
+					themes++
+				}
+
+				for _, pattern := range env.Ignore {
+					if err := config.ValidateIgnorePattern(pattern); err != nil {
+						fmt.Fprintf(stderr, "Skipped ignore pattern of environment %s: %v\n", env.Name, err)
+						continue
+					}
+					if !containsString(store.Ignore, pattern) && !containsString(patterns, pattern) {
+						patterns = append(patterns, pattern)
+					}
+				}
+			}
+
+			if len(patterns) > 0 {
+				if err := cfg.AddIgnorePatterns(store.Alias, patterns...); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported %d theme environments and %d ignore patterns into store %s\n", themes, len(patterns), store.Alias)
+			return nil
+		},
+	}
+}
+
+// storeEnvironments returns a store's theme environments as
+// shopify.theme.toml environments, sorted by name.
+func storeEnvironments(store *config.Store) []shopify.Environment {
+	if len(store.Themes) == 0 {
+		return []shopify.Environment{{Name: store.Alias, Store: store.StoreID, Ignore: store.Ignore}}
+	}
+
+	names := make([]string, 0, len(store.Themes))
+	for name := range store.Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	envs := make([]shopify.Environment, len(names))
+	for i, name := range names {
+		env := shopify.Environment{Name: name, Store: store.StoreID, Ignore: store.Ignore}
+		if themeID := store.Themes[name]; themeID == config.LiveTheme {
+			env.Live = true
+		} else {
+			env.Theme = themeID
+		}
+		envs[i] = env
+	}
+	return envs
+}
@@ -0,0 +1,249 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+)
+
+// setupEnvStore adds store alpha with a project directory holding the
+// given shopify.theme.toml, if any.
+func setupEnvStore(t *testing.T, h *testHelper, toml string) string {
+	t.Helper()
+	workspace := t.TempDir()
+	dir := filepath.Join(workspace, "alpha-dir")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if toml != "" {
+		if err := os.WriteFile(filepath.Join(dir, "shopify.theme.toml"), []byte(toml), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.mock.SetWorkspace(workspace)
+	h.mock.AddStore("alpha.myshopify.com", "alpha", "alpha-dir")
+	return dir
+}
+
+func TestEnvExportCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		toml    string
+		themes  map[string]string
+		protect bool
+		want    string
+		stdout  string
+		errMsg  string
+	}{
+		{
+			name:   "theme environments",
+			args:   []string{"env", "export", "alpha"},
+			themes: map[string]string{"staging": "222", "production": "live"},
+			want: `[environments.production]
+store = "alpha.myshopify.com"
+live = true
+ignore = ["config/settings_data.json"]
+
+[environments.staging]
+store = "alpha.myshopify.com"
+theme = "222"
+ignore = ["config/settings_data.json"]
+`,
+			stdout: "Exported 2 environments of store alpha",
+		},
+		{
+			name: "no theme environments",
+			args: []string{"env", "export", "alpha"},
+			want: `[environments.alpha]
+store = "alpha.myshopify.com"
+ignore = ["config/settings_data.json"]
+`,
+		},
+		{
+			name: "merges into an existing file",
+			args: []string{"env", "export", "alpha"},
+			toml: `# Team settings
+[environments.staging]
+store = "alpha"
+password = "shptka_secret" # from the Theme Access app
+theme = "111"
+
+[environments.other]
+store = "beta"
+`,
+			themes: map[string]string{"staging": "222"},
+			want: `# Team settings
+[environments.staging]
+store = "alpha.myshopify.com"
+password = "shptka_secret" # from the Theme Access app
+theme = "222"
+ignore = ["config/settings_data.json"]
+
+[environments.other]
+store = "beta"
+`,
+		},
+		{
+			name:    "protected live theme is skipped",
+			args:    []string{"env", "export", "alpha"},
+			themes:  map[string]string{"staging": "222", "production": "live"},
+			protect: true,
+			want: `[environments.staging]
+store = "alpha.myshopify.com"
+theme = "222"
+ignore = ["config/settings_data.json"]
+`,
+			stdout: "Skipped environment production",
+		},
+		{
+			name:   "dry run",
+			args:   []string{"env", "export", "alpha", "--dry-run"},
+			themes: map[string]string{"staging": "222"},
+			stdout: "[environments.staging]\nstore = \"alpha.myshopify.com\"\ntheme = \"222\"",
+		},
+		{
+			name:   "unknown store",
+			args:   []string{"env", "export", "gamma"},
+			errMsg: "store with alias \"gamma\" not found",
+		},
+		{
+			name:   "unreadable file",
+			args:   []string{"env", "export", "alpha"},
+			toml:   "[environments.staging]\nstore\n",
+			errMsg: "shopify.theme.toml line 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t)
+			dir := setupEnvStore(t, h, tt.toml)
+			h.mock.AddIgnorePatterns("alpha", "config/settings_data.json")
+			for env, themeID := range tt.themes {
+				if err := h.mock.SetThemeEnv("alpha", env, themeID); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.protect {
+				store := *h.mock.GetStore("alpha")
+				store.Protected = true
+				h.mock.UpdateStore("alpha", store)
+			}
+
+			h.setupCommand(NewEnvCommand(h.mock))
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(h.output.String(), tt.stdout) {
+				t.Errorf("output = %q, want %q", h.output.String(), tt.stdout)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, "shopify.theme.toml"))
+			if tt.want == "" {
+				if err == nil && string(data) != tt.toml {
+					t.Errorf("file changed on a dry run:\n%s", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("shopify.theme.toml =\n%s\nwant\n%s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvImportCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		toml       string
+		wantThemes map[string]string
+		wantIgnore []string
+		stdout     string
+		errMsg     string
+	}{
+		{
+			name: "environments for the store",
+			toml: `[environments.production]
+store = "alpha"
+live = true
+ignore = ["config/settings_data.json", "locales/*"]
+
+[environments.staging]
+store = "alpha.myshopify.com"
+theme = 222
+ignore = ["config/settings_data.json"]
+
+[environments.beta]
+store = "beta"
+theme = "333"
+
+[environments.bad-theme]
+theme = "main"
+`,
+			wantThemes: map[string]string{"production": config.LiveTheme, "staging": "222"},
+			wantIgnore: []string{"config/settings_data.json", "locales/*"},
+			stdout:     "Imported 2 theme environments and 2 ignore patterns into store alpha",
+		},
+		{
+			name:   "no environments",
+			toml:   "# nothing yet\n",
+			errMsg: "no environments in",
+		},
+		{
+			name:   "no file",
+			errMsg: "no environments in",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t)
+			setupEnvStore(t, h, tt.toml)
+
+			h.setupCommand(NewEnvCommand(h.mock))
+			h.cmd.SetArgs([]string{"env", "import", "alpha"})
+			err := h.cmd.Execute()
+
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			output := h.output.String()
+			for _, want := range []string{tt.stdout, "Skipped environment beta: it is for store beta", "Skipped environment bad-theme"} {
+				if !strings.Contains(output, want) {
+					t.Errorf("output = %q, want %q", output, want)
+				}
+			}
+			store := h.mock.GetStore("alpha")
+			if !reflect.DeepEqual(store.Themes, tt.wantThemes) {
+				t.Errorf("themes = %v, want %v", store.Themes, tt.wantThemes)
+			}
+			if !reflect.DeepEqual(store.Ignore, tt.wantIgnore) {
+				t.Errorf("ignore = %v, want %v", store.Ignore, tt.wantIgnore)
+			}
+		})
+	}
+}
@@ -1,6 +0,0 @@
-package commands
-
-import "os/exec"
-
-// execCommand is declared at package level for mocking in tests
-var execCommand = exec.Command 
\ No newline at end of file
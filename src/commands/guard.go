@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/colinxr/shopify-theme-manager/shopify"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+// addAllowLiveFlag registers --allow-live on a command that writes to a
+// theme.
+func addAllowLiveFlag(cmd *cobra.Command, allowLive *bool) {
+	cmd.Flags().BoolVar(allowLive, "allow-live", false, "Allow writing to the store's live theme after confirmation")
+}
+
+// guardLiveTheme refuses to let a write operation touch the store's live
+// theme. Writing to it requires allowLive and the user typing the store
+// alias, and is never allowed for protected stores. themeID is an ID or a
+// name, as the Shopify CLI accepts both. Themes that are not found in the
+// store's theme list are left for the Shopify CLI to reject.
+//
+// Without a theme the Shopify CLI asks for one and confirms the live theme
+// itself, so allowLive, which turns off that confirmation, is refused. It
+// is also refused for a theme name stm can't find, which the CLI might
+// match some other way.
+func guardLiveTheme(ctx context.Context, runner shopify.Runner, store *config.Store, themeID string, allowLive bool) error {
+	if themeID == "" {
+		if !allowLive {
+			return nil
+		}
+		if store.Protected {
+			return fmt.Errorf("store %q is protected; --allow-live is not allowed", store.Alias)
+		}
+		return fmt.Errorf("--allow-live needs a theme; pass --theme or --env so stm can confirm it")
+	}
+
+	theme, err := findTheme(ctx, runner, store.StoreID, themeID)
+	if err != nil {
+		return fmt.Errorf("checking the role of theme %s: %w", themeID, err)
+	}
+	if theme == nil {
+		if _, err := strconv.ParseInt(themeID, 10, 64); err != nil && allowLive {
+			return fmt.Errorf("theme %q not found in store %q; --allow-live needs a theme stm can check", themeID, store.Alias)
+		}
+		return nil
+	}
+	if theme.Role != shopify.RoleLive {
+		return nil
+	}
+
+	if store.Protected {
+		return fmt.Errorf("theme %s is the live theme of protected store %q; writing to it is not allowed", themeID, store.Alias)
+	}
+	if !allowLive {
+		return fmt.Errorf("theme %s is the live theme of store %q; pass --allow-live to write to it", themeID, store.Alias)
+	}
+	if !stdinIsTerminal() {
+		return fmt.Errorf("writing to the live theme of store %q must be confirmed in a terminal", store.Alias)
+	}
+
+	typed, err := runPrompt(promptui.Prompt{
+		Label: fmt.Sprintf("Theme %s is LIVE on %s. Type %q to continue", themeID, store.StoreID, store.Alias),
+	})
+	if err != nil {
+		return err
+	}
+	if typed != store.Alias {
+		return fmt.Errorf("confirmation did not match store alias %q; aborted", store.Alias)
+	}
+	return nil
+}
+
+// findTheme returns the store's theme with the ID or name theme, or nil
+// when there is none. An ID match wins over a theme named like an ID.
+func findTheme(ctx context.Context, runner shopify.Runner, storeID, theme string) (*shopify.Theme, error) {
+	themes, err := shopify.ListThemes(ctx, runner, storeID, shopify.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range themes {
+		if strconv.FormatInt(themes[i].ID, 10) == theme {
+			return &themes[i], nil
+		}
+	}
+	for i := range themes {
+		if strings.EqualFold(themes[i].Name, theme) {
+			return &themes[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/manifoldco/promptui"
+)
+
+// guardThemeList is a theme list with a live theme (1) and an unpublished
+// theme (2)
+const guardThemeList = `[{"id": 1, "name": "Dawn", "role": "live"}, {"id": 2, "name": "Draft", "role": "unpublished"}]`
+
+func TestGuardLiveTheme(t *testing.T) {
+	tests := []struct {
+		name       string
+		themeID    string
+		allowLive  bool
+		protected  bool
+		isTerminal bool
+		typed      string
+		promptErr  error
+		listErr    error
+		wantPrompt bool
+		errMsg     string
+	}{
+		{name: "no theme", themeID: ""},
+		{name: "no theme with --allow-live", themeID: "", allowLive: true, isTerminal: true, errMsg: "--allow-live needs a theme"},
+		{name: "no theme with --allow-live on protected store", themeID: "", allowLive: true, protected: true, errMsg: "is protected; --allow-live is not allowed"},
+		{name: "unpublished theme", themeID: "2"},
+		{name: "unknown theme", themeID: "99"},
+		{name: "unknown theme with --allow-live", themeID: "99", allowLive: true},
+		{name: "unpublished theme by name", themeID: "Draft", allowLive: true},
+		{name: "unknown theme name", themeID: "Nope"},
+		{name: "unknown theme name with --allow-live", themeID: "Nope", allowLive: true, isTerminal: true, errMsg: `theme "Nope" not found`},
+		{name: "live theme by name", themeID: "dawn", errMsg: "pass --allow-live"},
+		{
+			name:       "live theme by name confirmed",
+			themeID:    "Dawn",
+			allowLive:  true,
+			isTerminal: true,
+			typed:      "test-alias",
+			wantPrompt: true,
+		},
+		{
+			name:    "live theme without --allow-live",
+			themeID: "1",
+			errMsg:  "pass --allow-live",
+		},
+		{
+			name:       "live theme confirmed",
+			themeID:    "1",
+			allowLive:  true,
+			isTerminal: true,
+			typed:      "test-alias",
+			wantPrompt: true,
+		},
+		{
+			name:       "live theme with wrong confirmation",
+			themeID:    "1",
+			allowLive:  true,
+			isTerminal: true,
+			typed:      "yes",
+			wantPrompt: true,
+			errMsg:     "confirmation did not match",
+		},
+		{
+			name:       "live theme prompt interrupted",
+			themeID:    "1",
+			allowLive:  true,
+			isTerminal: true,
+			promptErr:  promptui.ErrInterrupt,
+			wantPrompt: true,
+			errMsg:     "^C",
+		},
+		{
+			name:      "live theme without a terminal",
+			themeID:   "1",
+			allowLive: true,
+			errMsg:    "must be confirmed in a terminal",
+		},
+		{
+			name:       "protected store",
+			themeID:    "1",
+			allowLive:  true,
+			protected:  true,
+			isTerminal: true,
+			typed:      "test-alias",
+			errMsg:     "protected store",
+		},
+		{
+			name:    "theme list fails",
+			themeID: "2",
+			listErr: errors.New("exit status 1"),
+			errMsg:  "checking the role of theme 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t)
+			h.mock.AddStore("test-store", "test-alias", "test-dir")
+			store := h.mock.GetStore("test-alias")
+			store.Protected = tt.protected
+			h.runner.Stdout = guardThemeList
+			h.runner.Err = tt.listErr
+
+			prompted := false
+			defer MockStdinIsTerminal(tt.isTerminal)()
+			defer MockPrompt(func(p promptui.Prompt) (string, error) {
+				prompted = true
+				return tt.typed, tt.promptErr
+			})()
+
+			err := guardLiveTheme(context.Background(), h.runner, store, tt.themeID, tt.allowLive)
+
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if prompted != tt.wantPrompt {
+				t.Errorf("prompted = %v, want %v", prompted, tt.wantPrompt)
+			}
+			if tt.themeID == "" && len(h.runner.Calls()) != 0 {
+				t.Error("shopify ran without a target theme")
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/spf13/cobra"
+)
+
+func NewIgnoreCommand(cfg config.Manager) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ignore",
+		Short: "Manage a store's default ignore patterns for push and pull",
+		Long: `Manage glob patterns that stm push and stm pull always pass to the
+Shopify CLI's --ignore flag for a store, such as config/settings_data.json.`,
+	}
+
+	cmd.AddCommand(
+		newIgnoreAddCommand(cfg),
+		newIgnoreRemoveCommand(cfg),
+		newIgnoreListCommand(cfg),
+	)
+	return cmd
+}
+
+func newIgnoreAddCommand(cfg config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:               "add [store-alias] <pattern>...",
+		Short:             "Add default ignore patterns",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeStoreAliases(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, patterns, err := listStoreArg(cfg, args, "patterns")
+			if err != nil {
+				return err
+			}
+			alias := store.Alias
+			if err := cfg.AddIgnorePatterns(alias, patterns...); err != nil {
+				return err
+			}
+
+			for _, pattern := range patterns {
+				fmt.Fprintf(cmd.OutOrStdout(), "Store %s now ignores %s\n", alias, pattern)
+			}
+			return nil
+		},
+	}
+}
+
+func newIgnoreRemoveCommand(cfg config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove [store-alias] <pattern>...",
+		Short: "Remove default ignore patterns",
+		Args:  cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			var patterns []string
+			if len(args) == 0 {
+				patterns = storeAliasCompletions(cfg, toComplete)
+			}
+			if store := completionStore(cfg, args); store != nil {
+				patterns = append(patterns, store.Ignore...)
+			}
+			return patterns, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, patterns, err := listStoreArg(cfg, args, "patterns")
+			if err != nil {
+				return err
+			}
+			alias := store.Alias
+			if err := cfg.RemoveIgnorePatterns(alias, patterns...); err != nil {
+				return err
+			}
+
+			for _, pattern := range patterns {
+				fmt.Fprintf(cmd.OutOrStdout(), "Store %s no longer ignores %s\n", alias, pattern)
+			}
+			return nil
+		},
+	}
+}
+
+func newIgnoreListCommand(cfg config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:               "list [store-alias]",
+		Short:             "List default ignore patterns",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeStoreAliases(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, _, err := fixedStoreArg(cfg, args, 0)
+			if err != nil {
+				return err
+			}
+
+			for _, pattern := range store.Ignore {
+				fmt.Fprintln(cmd.OutOrStdout(), pattern)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIgnoreCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		errMsg     string
+		wantIgnore []string
+		wantOutput string
+	}{
+		{
+			name:       "add patterns",
+			args:       []string{"ignore", "add", "test-alias", "locales/*", "templates/*.json"},
+			wantIgnore: []string{"config/settings_data.json", "locales/*", "templates/*.json"},
+		},
+		{
+			name:       "add existing pattern",
+			args:       []string{"ignore", "add", "test-alias", "config/settings_data.json"},
+			wantIgnore: []string{"config/settings_data.json"},
+		},
+		{
+			name:    "add invalid pattern",
+			args:    []string{"ignore", "add", "test-alias", "templates/["},
+			wantErr: true,
+			errMsg:  "invalid ignore pattern \"templates/[\"",
+		},
+		{
+			name:    "add without pattern",
+			args:    []string{"ignore", "add", "test-alias"},
+			wantErr: true,
+			errMsg:  "no patterns given for store test-alias",
+		},
+		{
+			name:       "remove pattern",
+			args:       []string{"ignore", "remove", "test-alias", "config/settings_data.json"},
+			wantIgnore: nil,
+		},
+		{
+			name:    "remove unknown pattern",
+			args:    []string{"ignore", "remove", "test-alias", "locales/*"},
+			wantErr: true,
+			errMsg:  "store \"test-alias\" has no ignore pattern \"locales/*\"",
+		},
+		{
+			name:       "list patterns",
+			args:       []string{"ignore", "list", "test-alias"},
+			wantIgnore: []string{"config/settings_data.json"},
+			wantOutput: "config/settings_data.json\n",
+		},
+		{
+			name:    "unknown store",
+			args:    []string{"ignore", "list", "invalid-store"},
+			wantErr: true,
+			errMsg:  "store with alias \"invalid-store\" not found",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newTestHelper(t)
+			h.mock.AddStore("test-store", "test-alias", "test-dir")
+			h.mock.AddIgnorePatterns("test-alias", "config/settings_data.json")
+
+			h.setupCommand(NewIgnoreCommand(h.mock))
+
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := h.mock.GetStore("test-alias").Ignore; !reflect.DeepEqual(got, tt.wantIgnore) {
+				t.Errorf("ignore = %v, want %v", got, tt.wantIgnore)
+			}
+			if tt.wantOutput != "" && h.output.String() != tt.wantOutput {
+				t.Errorf("output = %q, want %q", h.output.String(), tt.wantOutput)
+			}
+		})
+	}
+}
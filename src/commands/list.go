@@ -2,53 +2,54 @@ package commands
 
 import (
 	"fmt"
-	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/colinxr/shopify-theme-manager/shopify"
 	"github.com/spf13/cobra"
 )
 
-func NewListCommand(cfg config.Manager) *cobra.Command {
-	var themeName string
+func NewListCommand(cfg config.Manager, runner shopify.Runner) *cobra.Command {
+	var themeName, role, sortKey, output string
 
 	cmd := &cobra.Command{
-		Use:   "list <store-alias>",
-		Short: "List themes for a store",
-		Args:  cobra.ExactArgs(1),
+		Use:               "list [store-alias]",
+		Short:             "List themes for a store",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeStoreAliases(cfg),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			alias := args[0]
-			store := cfg.GetStore(alias)
-			if store == nil {
-				return fmt.Errorf("store with alias %q not found", alias)
+			store, _, err := storeArg(cfg, args, 0)
+			if err != nil {
+				return err
 			}
 
-			// Build base shopify CLI command
-			args = []string{"theme", "list", "--store", store.StoreID}
-
-			// Add name filter if provided
-			if themeName != "" {
-				args = append(args, "--name", themeName)
+			if role != "" && !shopify.ValidRole(role) {
+				return fmt.Errorf("invalid role %q (use %s)", role, strings.Join(shopify.Roles, ", "))
 			}
 
-			// Create command with all arguments
-			shopifyCmd := execCommand("shopify", args...)
-
-			// Set up the command to use the current terminal
-			shopifyCmd.Stdin = cmd.InOrStdin()
-			shopifyCmd.Stdout = cmd.OutOrStdout()
-			shopifyCmd.Stderr = cmd.ErrOrStderr()
+			themes, err := shopify.ListThemes(cmd.Context(), runner, store.StoreID, shopify.ListOptions{Name: themeName})
+			if err != nil {
+				return err
+			}
 
-			// Inherit the parent environment
-			shopifyCmd.Env = append([]string{
-				"TERM=" + os.Getenv("TERM"),
-				"HOME=" + os.Getenv("HOME"),
-				"PATH=" + os.Getenv("PATH"),
-			}, os.Environ()...)
+			themes = shopify.FilterByRole(themes, role)
+			if err := shopify.SortThemes(themes, sortKey); err != nil {
+				return err
+			}
 
-			return shopifyCmd.Run()
+			return renderOutput(cmd.OutOrStdout(), output, themes, func(tw *tabwriter.Writer) {
+				fmt.Fprintln(tw, "ID\tNAME\tROLE")
+				for _, theme := range themes {
+					fmt.Fprintf(tw, "%d\t%s\t%s\n", theme.ID, theme.Name, theme.Role)
+				}
+			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&themeName, "name", "n", "", "Filter themes by name")
+	cmd.Flags().StringVarP(&role, "role", "r", "", "Filter themes by role (live, unpublished, development)")
+	cmd.Flags().StringVar(&sortKey, "sort", shopify.SortByRole, "Sort themes by role, name or id")
+	cmd.Flags().StringVarP(&output, "output", "o", outputTable, "Output format (table, json, yaml)")
 	return cmd
 }
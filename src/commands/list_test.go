@@ -1,18 +1,19 @@
 package commands
 
 import (
-	"os/exec"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
 )
 
 func TestListCommand(t *testing.T) {
+	t.Parallel()
+
 	tests := []struct {
 		name      string
 		args      []string
 		setupMock func(*testHelper)
-		wantCmd   string
 		wantArgs  []string
 		wantErr   bool
 		errMsg    string
@@ -23,8 +24,7 @@ func TestListCommand(t *testing.T) {
 			setupMock: func(h *testHelper) {
 				h.mock.AddStore("test-store", "test-alias", "test-dir")
 			},
-			wantCmd:  "shopify",
-			wantArgs: []string{"theme", "list", "--store", "test-store"},
+			wantArgs: []string{"theme", "list", "--store", "test-store", "--json"},
 			wantErr:  false,
 		},
 		{
@@ -33,8 +33,7 @@ func TestListCommand(t *testing.T) {
 			setupMock: func(h *testHelper) {
 				h.mock.AddStore("test-store", "test-alias", "test-dir")
 			},
-			wantCmd:  "shopify",
-			wantArgs: []string{"theme", "list", "--store", "test-store", "--name", "dawn"},
+			wantArgs: []string{"theme", "list", "--store", "test-store", "--name", "dawn", "--json"},
 			wantErr:  false,
 		},
 		{
@@ -43,39 +42,27 @@ func TestListCommand(t *testing.T) {
 			wantErr: true,
 			errMsg:  "store with alias \"invalid-store\" not found",
 		},
-		{
-			name:    "missing store alias",
-			args:    []string{"list"},
-			wantErr: true,
-			errMsg:  "accepts 1 arg(s), received 0",
-		},
 		{
 			name:    "too many arguments",
 			args:    []string{"list", "test-alias", "extra"},
 			wantErr: true,
-			errMsg:  "accepts 1 arg(s), received 2",
+			errMsg:  "accepts at most 1 arg(s), received 2",
 		},
 	}
 
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
 			h := newTestHelper(t)
 
 			if tt.setupMock != nil {
 				tt.setupMock(h)
 			}
 
-			var executedCmd string
-			var executedArgs []string
+			h.runner.Stdout = "[]"
 
-			cleanup := MockExecCommand(func(cmd string, args ...string) *exec.Cmd {
-				executedCmd = cmd
-				executedArgs = args
-				return exec.Command("true") // Use 'true' command which always succeeds
-			})
-			defer cleanup()
-
-			cmd := NewListCommand(h.mock)
+			cmd := NewListCommand(h.mock, h.runner)
 			h.setupCommand(cmd)
 
 			h.cmd.SetArgs(tt.args)
@@ -95,30 +82,28 @@ func TestListCommand(t *testing.T) {
 				return
 			}
 
-			if tt.wantCmd != "" && executedCmd != tt.wantCmd {
-				t.Errorf("executed command = %s, want %s", executedCmd, tt.wantCmd)
+			call := h.runner.LastCall()
+			if call == nil {
+				t.Fatal("shopify was not run")
 			}
 
-			if tt.wantArgs != nil && !reflect.DeepEqual(executedArgs, tt.wantArgs) {
-				t.Errorf("executed args = %v, want %v", executedArgs, tt.wantArgs)
+			if tt.wantArgs != nil && !reflect.DeepEqual(call.Args, tt.wantArgs) {
+				t.Errorf("executed args = %v, want %v", call.Args, tt.wantArgs)
 			}
 		})
 	}
 }
 
 func TestListCommand_ExecutionFailure(t *testing.T) {
+	t.Parallel()
 	h := newTestHelper(t)
 
 	// Setup mock store
 	h.mock.AddStore("test-store", "test-alias", "test-dir")
 
-	cleanup := MockExecCommand(func(cmd string, args ...string) *exec.Cmd {
-		// Return a command that will fail
-		return exec.Command("nonexistent-command")
-	})
-	defer cleanup()
+	h.runner.Err = errors.New(`exec: "shopify": executable file not found in $PATH`)
 
-	cmd := NewListCommand(h.mock)
+	cmd := NewListCommand(h.mock, h.runner)
 	h.setupCommand(cmd)
 
 	h.cmd.SetArgs([]string{"list", "test-alias"})
@@ -129,31 +114,109 @@ func TestListCommand_ExecutionFailure(t *testing.T) {
 	}
 }
 
-func TestListCommand_OutputCapture(t *testing.T) {
-	h := newTestHelper(t)
+func TestListCommand_Output(t *testing.T) {
+	t.Parallel()
 
-	// Setup mock store
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "table sorted by role",
+			args: []string{"list", "test-alias"},
+			want: "ID   NAME          ROLE\n" +
+				"111  Dawn          live\n" +
+				"222  Dawn staging  unpublished\n" +
+				"333  Refresh       development\n",
+		},
+		{
+			name: "filter by role",
+			args: []string{"list", "test-alias", "--role", "development"},
+			want: "ID   NAME     ROLE\n" +
+				"333  Refresh  development\n",
+		},
+		{
+			name: "sort by name within role",
+			args: []string{"list", "test-alias", "--sort", "name", "-r", "unpublished"},
+			want: "ID   NAME          ROLE\n" +
+				"222  Dawn staging  unpublished\n",
+		},
+		{
+			name: "json output",
+			args: []string{"list", "test-alias", "--role", "live", "--output", "json"},
+			want: "[\n  {\n    \"id\": 111,\n    \"name\": \"Dawn\",\n    \"role\": \"live\"\n  }\n]\n",
+		},
+		{
+			name:    "invalid role",
+			args:    []string{"list", "test-alias", "--role", "main"},
+			wantErr: true,
+			errMsg:  "invalid role \"main\"",
+		},
+		{
+			name:    "invalid sort key",
+			args:    []string{"list", "test-alias", "--sort", "size"},
+			wantErr: true,
+			errMsg:  "invalid sort key \"size\"",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newTestHelper(t)
+			h.mock.AddStore("test-store", "test-alias", "test-dir")
+
+			// Deliberately out of order
+			h.runner.Stdout = `[
+				{"id": 333, "name": "Refresh", "role": "development"},
+				{"id": 222, "name": "Dawn staging", "role": "unpublished"},
+				{"id": 111, "name": "Dawn", "role": "live"}
+			]`
+
+			cmd := NewListCommand(h.mock, h.runner)
+			h.setupCommand(cmd)
+
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := h.output.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListCommand_InvalidJSON(t *testing.T) {
+	t.Parallel()
+	h := newTestHelper(t)
 	h.mock.AddStore("test-store", "test-alias", "test-dir")
 
-	expectedOutput := "mocked theme list output"
-	cleanup := MockExecCommand(func(cmd string, args ...string) *exec.Cmd {
-		return exec.Command("echo", expectedOutput)
-	})
-	defer cleanup()
+	h.runner.Stdout = "not json"
 
-	cmd := NewListCommand(h.mock)
+	cmd := NewListCommand(h.mock, h.runner)
 	h.setupCommand(cmd)
 
 	h.cmd.SetArgs([]string{"list", "test-alias"})
 	err := h.cmd.Execute()
 
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-		return
-	}
-
-	output := h.output.String()
-	if !strings.Contains(output, expectedOutput) {
-		t.Errorf("output = %q, want to contain %q", output, expectedOutput)
+	if err == nil || !strings.Contains(err.Error(), "failed to parse theme list") {
+		t.Errorf("error = %v, want parse error", err)
 	}
 }
@@ -9,8 +9,10 @@ import (
 
 // MockConfig implements config.Manager for testing
 type MockConfig struct {
-	stores    []config.Store
-	workspace string
+	stores       []config.Store
+	workspace    string
+	configPath   string
+	currentStore string
 }
 
 func NewMockConfig() config.Manager {
@@ -20,6 +22,9 @@ func NewMockConfig() config.Manager {
 }
 
 func (m *MockConfig) AddStore(storeID, alias, projectDir string) error {
+	if m.GetStore(alias) != nil {
+		return fmt.Errorf("store with alias %q already exists", alias)
+	}
 	m.stores = append(m.stores, config.Store{
 		StoreID:    storeID,
 		Alias:      alias,
@@ -37,6 +42,168 @@ func (m *MockConfig) GetStore(alias string) *config.Store {
 	return nil
 }
 
+func (m *MockConfig) ListStores() []config.Store {
+	stores := make([]config.Store, len(m.stores))
+	copy(stores, m.stores)
+	return stores
+}
+
+func (m *MockConfig) UpdateStore(alias string, store config.Store) error {
+	for i := range m.stores {
+		if m.stores[i].Alias != alias {
+			continue
+		}
+		if store.Alias != alias && m.GetStore(store.Alias) != nil {
+			return fmt.Errorf("store with alias %q already exists", store.Alias)
+		}
+		m.stores[i] = store
+		if m.currentStore == alias {
+			m.currentStore = store.Alias
+		}
+		return nil
+	}
+	return fmt.Errorf("store with alias %q not found", alias)
+}
+
+func (m *MockConfig) RemoveStore(alias string) error {
+	for i, store := range m.stores {
+		if store.Alias == alias {
+			m.stores = append(m.stores[:i], m.stores[i+1:]...)
+			if m.currentStore == alias {
+				m.currentStore = ""
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("store with alias %q not found", alias)
+}
+
+func (m *MockConfig) SetThemeEnv(alias, env, themeID string) error {
+	if err := config.ValidateThemeEnv(env); err != nil {
+		return err
+	}
+	if err := config.ValidateThemeID(themeID); err != nil {
+		return err
+	}
+	for i := range m.stores {
+		if m.stores[i].Alias == alias {
+			if m.stores[i].Themes == nil {
+				m.stores[i].Themes = make(map[string]string)
+			}
+			m.stores[i].Themes[env] = themeID
+			return nil
+		}
+	}
+	return fmt.Errorf("store with alias %q not found", alias)
+}
+
+func (m *MockConfig) UnsetThemeEnv(alias, env string) error {
+	for i := range m.stores {
+		if m.stores[i].Alias != alias {
+			continue
+		}
+		if _, ok := m.stores[i].Themes[env]; !ok {
+			return fmt.Errorf("store %q has no theme environment %q", alias, env)
+		}
+		delete(m.stores[i].Themes, env)
+		return nil
+	}
+	return fmt.Errorf("store with alias %q not found", alias)
+}
+
+func (m *MockConfig) AddIgnorePatterns(alias string, patterns ...string) error {
+	for _, pattern := range patterns {
+		if err := config.ValidateIgnorePattern(pattern); err != nil {
+			return err
+		}
+	}
+	for i := range m.stores {
+		if m.stores[i].Alias != alias {
+			continue
+		}
+		for _, pattern := range patterns {
+			if !containsString(m.stores[i].Ignore, pattern) {
+				m.stores[i].Ignore = append(m.stores[i].Ignore, pattern)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("store with alias %q not found", alias)
+}
+
+func (m *MockConfig) RemoveIgnorePatterns(alias string, patterns ...string) error {
+	for i := range m.stores {
+		if m.stores[i].Alias != alias {
+			continue
+		}
+		var kept []string
+		for _, pattern := range patterns {
+			if !containsString(m.stores[i].Ignore, pattern) {
+				return fmt.Errorf("store %q has no ignore pattern %q", alias, pattern)
+			}
+		}
+		for _, pattern := range m.stores[i].Ignore {
+			if !containsString(patterns, pattern) {
+				kept = append(kept, pattern)
+			}
+		}
+		m.stores[i].Ignore = kept
+		return nil
+	}
+	return fmt.Errorf("store with alias %q not found", alias)
+}
+
+func (m *MockConfig) AddTags(alias string, tags ...string) error {
+	for _, tag := range tags {
+		if err := config.ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+	for i := range m.stores {
+		if m.stores[i].Alias != alias {
+			continue
+		}
+		for _, tag := range tags {
+			if !m.stores[i].HasTag(tag) {
+				m.stores[i].Tags = append(m.stores[i].Tags, tag)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("store with alias %q not found", alias)
+}
+
+func (m *MockConfig) RemoveTags(alias string, tags ...string) error {
+	for i := range m.stores {
+		if m.stores[i].Alias != alias {
+			continue
+		}
+		var kept []string
+		for _, tag := range tags {
+			if !m.stores[i].HasTag(tag) {
+				return fmt.Errorf("store %q has no tag %q", alias, tag)
+			}
+		}
+		for _, tag := range m.stores[i].Tags {
+			if !containsString(tags, tag) {
+				kept = append(kept, tag)
+			}
+		}
+		m.stores[i].Tags = kept
+		return nil
+	}
+	return fmt.Errorf("store with alias %q not found", alias)
+}
+
+func containsString(patterns []string, pattern string) bool {
+	for _, p := range patterns {
+		if p == pattern {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *MockConfig) SetWorkspace(path string) error {
 	// Check for null bytes in path
 	if strings.Contains(path, "\x00") {
@@ -49,3 +216,30 @@ func (m *MockConfig) SetWorkspace(path string) error {
 func (m *MockConfig) GetWorkspace() string {
 	return m.workspace
 }
+
+func (m *MockConfig) SetCurrentStore(alias string) error {
+	if alias != "" && m.GetStore(alias) == nil {
+		return fmt.Errorf("store with alias %q not found", alias)
+	}
+	m.currentStore = alias
+	return nil
+}
+
+func (m *MockConfig) GetCurrentStore() string {
+	return m.currentStore
+}
+
+func (m *MockConfig) ConfigPath() string {
+	return m.configPath
+}
+
+func (m *MockConfig) MigrationPlan() (*config.MigrationPlan, error) {
+	return &config.MigrationPlan{
+		FromVersion: config.CurrentVersion,
+		ToVersion:   config.CurrentVersion,
+	}, nil
+}
+
+func (m *MockConfig) Migrate() error {
+	return nil
+}
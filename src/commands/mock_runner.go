@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/colinxr/shopify-theme-manager/shopify"
+)
+
+// MockRunner implements shopify.Runner for testing. It records every
+// invocation and answers with canned output instead of running the CLI.
+type MockRunner struct {
+	mu    sync.Mutex
+	calls []shopify.Invocation
+
+	// Stdout is written to the invocation's stdout
+	Stdout string
+	// Err is returned from Run
+	Err error
+	// Respond, when set, replaces Stdout and Err for each invocation
+	Respond func(inv shopify.Invocation) (string, error)
+}
+
+func NewMockRunner() *MockRunner {
+	return &MockRunner{}
+}
+
+func (m *MockRunner) Run(ctx context.Context, inv shopify.Invocation) error {
+	m.mu.Lock()
+	m.calls = append(m.calls, inv)
+	m.mu.Unlock()
+
+	stdout, err := m.Stdout, m.Err
+	if m.Respond != nil {
+		stdout, err = m.Respond(inv)
+	}
+
+	if stdout != "" && inv.Stdout != nil {
+		if _, writeErr := io.WriteString(inv.Stdout, stdout); writeErr != nil {
+			return writeErr
+		}
+	}
+	return err
+}
+
+// Calls returns the invocations recorded so far
+func (m *MockRunner) Calls() []shopify.Invocation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	calls := make([]shopify.Invocation, len(m.calls))
+	copy(calls, m.calls)
+	return calls
+}
+
+// LastCall returns the most recent invocation, or nil if there was none
+func (m *MockRunner) LastCall() *shopify.Invocation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.calls) == 0 {
+		return nil
+	}
+	call := m.calls[len(m.calls)-1]
+	return &call
+}
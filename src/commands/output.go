@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported values for --output flags
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// renderOutput writes data to w in the requested format. Table output is
+// delegated to writeTable, which receives an aligned tabwriter.
+func renderOutput(w io.Writer, format string, data interface{}, writeTable func(tw *tabwriter.Writer)) error {
+	switch format {
+	case outputTable, "":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		writeTable(tw)
+		return tw.Flush()
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	case outputYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(data); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		return fmt.Errorf("unsupported output format %q (use table, json or yaml)", format)
+	}
+}
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"errors"
+	"os"
+
+	"github.com/chzyer/readline"
+	"github.com/manifoldco/promptui"
+)
+
+// Declare runPrompt at package level for production use
+var runPrompt = func(p promptui.Prompt) (string, error) {
+	return p.Run()
+}
+
+// stdinIsTerminal reports whether prompts can be shown. It is declared at
+// package level for mocking in tests.
+var stdinIsTerminal = func() bool {
+	return readline.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// confirm asks a yes/no question and reports whether the user agreed.
+func confirm(label string) (bool, error) {
+	_, err := runPrompt(promptui.Prompt{
+		Label:     label,
+		IsConfirm: true,
+	})
+	if errors.Is(err, promptui.ErrAbort) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
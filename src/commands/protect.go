@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/spf13/cobra"
+)
+
+func NewProtectCommand(cfg config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "protect [store-alias]",
+		Short: "Forbid writes to a store's live theme",
+		Long: `Mark a store as protected. Commands that write to a theme refuse to
+touch a protected store's live theme, even with --allow-live.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeStoreAliases(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setProtected(cmd, cfg, args, true)
+		},
+	}
+}
+
+func NewUnprotectCommand(cfg config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:               "unprotect [store-alias]",
+		Short:             "Allow writes to a store's live theme with --allow-live",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeStoreAliases(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setProtected(cmd, cfg, args, false)
+		},
+	}
+}
+
+func setProtected(cmd *cobra.Command, cfg config.Manager, args []string, protected bool) error {
+	store, _, err := fixedStoreArg(cfg, args, 0)
+	if err != nil {
+		return err
+	}
+	alias := store.Alias
+
+	updated := *store
+	updated.Protected = protected
+	if err := cfg.UpdateStore(alias, updated); err != nil {
+		return err
+	}
+
+	if protected {
+		fmt.Fprintf(cmd.OutOrStdout(), "Store %s is now protected\n", alias)
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "Store %s is no longer protected\n", alias)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProtectCommands(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		args          []string
+		protected     bool
+		wantProtected bool
+		wantErr       bool
+		errMsg        string
+	}{
+		{
+			name:          "protect store",
+			args:          []string{"protect", "test-alias"},
+			wantProtected: true,
+		},
+		{
+			name:          "protect protected store",
+			args:          []string{"protect", "test-alias"},
+			protected:     true,
+			wantProtected: true,
+		},
+		{
+			name:          "unprotect store",
+			args:          []string{"unprotect", "test-alias"},
+			protected:     true,
+			wantProtected: false,
+		},
+		{
+			name:    "store not found",
+			args:    []string{"protect", "invalid-store"},
+			wantErr: true,
+			errMsg:  "store with alias \"invalid-store\" not found",
+		},
+		{
+			name:    "too many arguments",
+			args:    []string{"unprotect", "test-alias", "extra"},
+			wantErr: true,
+			errMsg:  "accepts at most 1 arg(s), received 2",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newTestHelper(t)
+			h.mock.AddStore("test-store", "test-alias", "test-dir")
+			store := *h.mock.GetStore("test-alias")
+			store.Protected = tt.protected
+			h.mock.UpdateStore("test-alias", store)
+
+			h.setupCommand(NewProtectCommand(h.mock))
+			h.setupCommand(NewUnprotectCommand(h.mock))
+
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := h.mock.GetStore("test-alias").Protected; got != tt.wantProtected {
+				t.Errorf("Protected = %v, want %v", got, tt.wantProtected)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/colinxr/shopify-theme-manager/shopify"
+	"github.com/spf13/cobra"
+)
+
+// syncFlags holds the flags shared by push and pull
+type syncFlags struct {
+	theme           string
+	env             string
+	only            []string
+	ignore          []string
+	noDefaultIgnore bool
+	noDelete        bool
+	allowLive       bool
+}
+
+func NewPushCommand(cfg config.Manager, runner shopify.Runner) *cobra.Command {
+	var flags syncFlags
+
+	cmd := &cobra.Command{
+		Use:   "push [store-alias] [--theme <id>|--env <name>]",
+		Short: "Upload the store's project directory to a theme",
+		Long: `Upload the store's project directory to a theme with "shopify theme push".
+
+The store's default ignore patterns (see "stm ignore") are always passed to
+--ignore unless --no-default-ignore is given. Pushing to the live theme
+requires --allow-live and typing the store alias to confirm.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeStoreAliases(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSync(cmd, cfg, runner, args, &flags, true)
+		},
+	}
+
+	addSyncFlags(cmd, cfg, runner, &flags)
+	addAllowLiveFlag(cmd, &flags.allowLive)
+	return cmd
+}
+
+func NewPullCommand(cfg config.Manager, runner shopify.Runner) *cobra.Command {
+	var flags syncFlags
+
+	cmd := &cobra.Command{
+		Use:   "pull [store-alias] [--theme <id>|--env <name>]",
+		Short: "Download a theme into the store's project directory",
+		Long: `Download a theme into the store's project directory with "shopify theme pull".
+
+The store's default ignore patterns (see "stm ignore") are always passed to
+--ignore unless --no-default-ignore is given.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeStoreAliases(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSync(cmd, cfg, runner, args, &flags, false)
+		},
+	}
+
+	addSyncFlags(cmd, cfg, runner, &flags)
+	return cmd
+}
+
+func addSyncFlags(cmd *cobra.Command, cfg config.Manager, runner shopify.Runner, flags *syncFlags) {
+	cmd.Flags().StringVarP(&flags.theme, "theme", "t", "", "Theme ID, or \"live\" for the published theme")
+	cmd.Flags().StringArrayVar(&flags.only, "only", nil, "Only sync files matching the glob (repeatable)")
+	cmd.Flags().StringArrayVar(&flags.ignore, "ignore", nil, "Skip files matching the glob (repeatable)")
+	cmd.Flags().BoolVar(&flags.noDefaultIgnore, "no-default-ignore", false, "Don't apply the store's default ignore patterns")
+	cmd.Flags().BoolVar(&flags.noDelete, "nodelete", false, "Keep files at the destination that are missing at the source")
+	addThemeEnvFlag(cmd, cfg, &flags.env)
+
+	cmd.RegisterFlagCompletionFunc("theme", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return themeCompletions(cmd, cfg, runner, args[0], toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// runSync runs "shopify theme push" or "shopify theme pull" for a store
+// from its project directory, attached to the terminal.
+func runSync(cmd *cobra.Command, cfg config.Manager, runner shopify.Runner, args []string, flags *syncFlags, push bool) error {
+	store, _, err := storeArg(cfg, args, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, patterns := range [][]string{flags.only, flags.ignore} {
+		for _, pattern := range patterns {
+			if err := config.ValidateIgnorePattern(pattern); err != nil {
+				return err
+			}
+		}
+	}
+
+	themeID, err := resolveThemeID(cmd.Context(), runner, store, flags.theme, flags.env)
+	if err != nil {
+		return err
+	}
+
+	if push {
+		if err := guardLiveTheme(cmd.Context(), runner, store, themeID, flags.allowLive); err != nil {
+			return err
+		}
+	}
+
+	opts := shopify.SyncOptions{
+		Theme:     themeID,
+		Only:      flags.only,
+		NoDelete:  flags.noDelete,
+		AllowLive: flags.allowLive,
+	}
+	if !flags.noDefaultIgnore {
+		opts.Ignore = append(opts.Ignore, store.Ignore...)
+	}
+	opts.Ignore = append(opts.Ignore, flags.ignore...)
+
+	cliArgs := shopify.PullArgs(store.StoreID, opts)
+	if push {
+		cliArgs = shopify.PushArgs(store.StoreID, opts)
+	}
+
+	return runner.Run(cmd.Context(), shopify.Invocation{
+		Args:   cliArgs,
+		Dir:    store.ProjectPath(cfg.GetWorkspace()),
+		Stdin:  cmd.InOrStdin(),
+		Stdout: cmd.OutOrStdout(),
+		Stderr: cmd.ErrOrStderr(),
+	})
+}
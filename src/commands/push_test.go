@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/colinxr/shopify-theme-manager/shopify"
+	"github.com/manifoldco/promptui"
+)
+
+func TestPushPullCommands(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		args      []string
+		protected bool
+		wantArgs  []string
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name:     "push with default ignore patterns",
+			args:     []string{"push", "test-alias", "--theme", "2"},
+			wantArgs: []string{"theme", "push", "--store", "test-store", "--theme", "2", "--ignore", "config/settings_data.json"},
+		},
+		{
+			name: "push with only and ignore",
+			args: []string{"push", "test-alias", "-t", "2", "--only", "sections/*", "--only", "snippets/*", "--ignore", "locales/*", "--nodelete"},
+			wantArgs: []string{"theme", "push", "--store", "test-store", "--theme", "2", "--only", "sections/*", "--only", "snippets/*",
+				"--ignore", "config/settings_data.json", "--ignore", "locales/*", "--nodelete"},
+		},
+		{
+			name:     "push without default ignore patterns",
+			args:     []string{"push", "test-alias", "--env", "staging", "--no-default-ignore"},
+			wantArgs: []string{"theme", "push", "--store", "test-store", "--theme", "2"},
+		},
+		{
+			name:     "push lets the CLI choose a theme",
+			args:     []string{"push", "test-alias"},
+			wantArgs: []string{"theme", "push", "--store", "test-store", "--ignore", "config/settings_data.json"},
+		},
+		{
+			name:    "push to live theme",
+			args:    []string{"push", "test-alias", "--theme", "live"},
+			wantErr: true,
+			errMsg:  "pass --allow-live",
+		},
+		{
+			name:    "push to live theme by name",
+			args:    []string{"push", "test-alias", "--theme", "Dawn"},
+			wantErr: true,
+			errMsg:  "pass --allow-live",
+		},
+		{
+			name:      "push to live theme by name on protected store",
+			args:      []string{"push", "test-alias", "--theme", "dawn", "--allow-live"},
+			protected: true,
+			wantErr:   true,
+			errMsg:    "live theme of protected store",
+		},
+		{
+			name:    "push to unknown theme name with --allow-live",
+			args:    []string{"push", "test-alias", "--theme", "Nope", "--allow-live"},
+			wantErr: true,
+			errMsg:  "theme \"Nope\" not found",
+		},
+		{
+			name:    "push without a theme and --allow-live",
+			args:    []string{"push", "test-alias", "--allow-live"},
+			wantErr: true,
+			errMsg:  "--allow-live needs a theme",
+		},
+		{
+			name:      "push without a theme and --allow-live to protected store",
+			args:      []string{"push", "test-alias", "--allow-live"},
+			protected: true,
+			wantErr:   true,
+			errMsg:    "store \"test-alias\" is protected",
+		},
+		{
+			name:     "pull from live theme",
+			args:     []string{"pull", "test-alias", "--theme", "live"},
+			wantArgs: []string{"theme", "pull", "--store", "test-store", "--theme", "1", "--ignore", "config/settings_data.json"},
+		},
+		{
+			name:     "pull with environment",
+			args:     []string{"pull", "test-alias", "-e", "staging", "--only", "templates/*.json"},
+			wantArgs: []string{"theme", "pull", "--store", "test-store", "--theme", "2", "--only", "templates/*.json", "--ignore", "config/settings_data.json"},
+		},
+		{
+			name:    "pull has no --allow-live",
+			args:    []string{"pull", "test-alias", "--allow-live"},
+			wantErr: true,
+			errMsg:  "unknown flag: --allow-live",
+		},
+		{
+			name:    "invalid glob",
+			args:    []string{"push", "test-alias", "--theme", "2", "--only", "sections/["},
+			wantErr: true,
+			errMsg:  "invalid ignore pattern \"sections/[\"",
+		},
+		{
+			name:    "theme and environment",
+			args:    []string{"pull", "test-alias", "--theme", "2", "--env", "staging"},
+			wantErr: true,
+			errMsg:  "not both",
+		},
+		{
+			name:    "store not found",
+			args:    []string{"push", "invalid-store"},
+			wantErr: true,
+			errMsg:  "store with alias \"invalid-store\" not found",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newTestHelper(t)
+
+			h.mock.SetWorkspace("/workspace")
+			h.mock.AddStore("test-store", "test-alias", "test-dir")
+			h.mock.SetThemeEnv("test-alias", "staging", "2")
+			h.mock.AddIgnorePatterns("test-alias", "config/settings_data.json")
+			if tt.protected {
+				store := *h.mock.GetStore("test-alias")
+				store.Protected = true
+				h.mock.UpdateStore("test-alias", store)
+			}
+			h.runner.Respond = func(inv shopify.Invocation) (string, error) {
+				if inv.Args[1] == "list" {
+					return guardThemeList, nil
+				}
+				return "", nil
+			}
+
+			h.setupCommand(NewPushCommand(h.mock, h.runner))
+			h.setupCommand(NewPullCommand(h.mock, h.runner))
+
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				for _, call := range h.runner.Calls() {
+					if call.Args[1] != "list" {
+						t.Errorf("shopify ran %v, want no sync", call.Args)
+					}
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			call := h.runner.LastCall()
+			if call == nil {
+				t.Fatal("shopify was not run")
+			}
+			if !reflect.DeepEqual(call.Args, tt.wantArgs) {
+				t.Errorf("executed args = %v, want %v", call.Args, tt.wantArgs)
+			}
+			if want := "/workspace/test-dir"; call.Dir != want {
+				t.Errorf("working directory = %s, want %s", call.Dir, want)
+			}
+			if call.Stdin == nil || call.Stdout != h.output || call.Stderr != h.output {
+				t.Error("shopify is not attached to the command's stdio")
+			}
+		})
+	}
+}
+
+func TestPushCommand_ExecutionFailure(t *testing.T) {
+	t.Parallel()
+	h := newTestHelper(t)
+
+	h.mock.AddStore("test-store", "test-alias", ".")
+	h.runner.Err = errors.New("exit status 1")
+
+	h.setupCommand(NewPushCommand(h.mock, h.runner))
+
+	h.cmd.SetArgs([]string{"push", "test-alias"})
+	if err := h.cmd.Execute(); err == nil {
+		t.Error("expected error from failed command execution but got none")
+	}
+}
+
+func TestPushCommand_LiveThemeByName(t *testing.T) {
+	h := newTestHelper(t)
+
+	h.mock.AddStore("test-store", "test-alias", "test-dir")
+	h.runner.Respond = func(inv shopify.Invocation) (string, error) {
+		if inv.Args[1] == "list" {
+			return guardThemeList, nil
+		}
+		return "", nil
+	}
+
+	prompted := false
+	defer MockStdinIsTerminal(true)()
+	defer MockPrompt(func(p promptui.Prompt) (string, error) {
+		prompted = true
+		return "test-alias", nil
+	})()
+
+	h.setupCommand(NewPushCommand(h.mock, h.runner))
+	h.cmd.SetArgs([]string{"push", "test-alias", "--theme", "Dawn", "--allow-live"})
+	if err := h.cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !prompted {
+		t.Error("pushing to the live theme by name was not confirmed")
+	}
+	want := []string{"theme", "push", "--store", "test-store", "--theme", "Dawn", "--allow-live"}
+	if call := h.runner.LastCall(); call == nil || !reflect.DeepEqual(call.Args, want) {
+		t.Errorf("last call = %v, want args %v", call, want)
+	}
+}
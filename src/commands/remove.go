@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/spf13/cobra"
+)
+
+func NewRemoveCommand(cfg config.Manager) *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "remove <store-alias>",
+		Short: "Remove a Shopify store configuration",
+		Long: `Remove a store from the config. Unlike most commands, remove always
+needs the store alias and never falls back to the current store, so a store
+can't be removed by running it in the wrong directory.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeStoreAliases(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			alias := args[0]
+			if cfg.GetStore(alias) == nil {
+				return fmt.Errorf("store with alias %q not found", alias)
+			}
+
+			if !yes {
+				ok, err := confirm(fmt.Sprintf("Remove store %s", alias))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					fmt.Fprintln(cmd.OutOrStdout(), "Aborted")
+					return nil
+				}
+			}
+
+			if err := cfg.RemoveStore(alias); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Store %s removed successfully\n", alias)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation prompt")
+	return cmd
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/manifoldco/promptui"
+)
+
+func TestRemoveCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		confirmErr  error
+		wantErr     bool
+		errMsg      string
+		wantRemoved bool
+		wantOutput  string
+	}{
+		{
+			name:        "remove after confirmation",
+			args:        []string{"remove", "test-alias"},
+			wantRemoved: true,
+			wantOutput:  "Store test-alias removed successfully",
+		},
+		{
+			name:        "remove without prompting",
+			args:        []string{"remove", "test-alias", "--yes"},
+			confirmErr:  errors.New("unexpected prompt"),
+			wantRemoved: true,
+		},
+		{
+			name:       "confirmation declined",
+			args:       []string{"remove", "test-alias"},
+			confirmErr: promptui.ErrAbort,
+			wantOutput: "Aborted",
+		},
+		{
+			name:       "confirmation prompt fails",
+			args:       []string{"remove", "test-alias"},
+			confirmErr: errors.New("prompt failed"),
+			wantErr:    true,
+			errMsg:     "prompt failed",
+		},
+		{
+			name:    "store not found",
+			args:    []string{"remove", "invalid-store"},
+			wantErr: true,
+			errMsg:  "store with alias \"invalid-store\" not found",
+		},
+		{
+			name:    "missing store alias",
+			args:    []string{"remove"},
+			wantErr: true,
+			errMsg:  "accepts 1 arg(s), received 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t)
+			h.mock.AddStore("test-store", "test-alias", "test-dir")
+
+			cleanup := MockPrompt(func(p promptui.Prompt) (string, error) {
+				if tt.confirmErr != nil {
+					return "", tt.confirmErr
+				}
+				return "y", nil
+			})
+			defer cleanup()
+
+			cmd := NewRemoveCommand(h.mock)
+			h.setupCommand(cmd)
+
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			removed := h.mock.GetStore("test-alias") == nil
+			if removed != tt.wantRemoved {
+				t.Errorf("store removed = %v, want %v", removed, tt.wantRemoved)
+			}
+
+			if tt.wantOutput != "" && !strings.Contains(h.output.String(), tt.wantOutput) {
+				t.Errorf("output = %q, want to contain %q", h.output.String(), tt.wantOutput)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/spf13/cobra"
+)
+
+func NewRenameCommand(cfg config.Manager) *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "rename <old-alias> <new-alias>",
+		Short: "Rename a Shopify store alias",
+		Long: `Rename a store's alias. Unlike most commands, rename always needs the old
+alias and never falls back to the current store, so the alias being changed
+is always the one typed.`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeStoreAliases(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldAlias, newAlias := args[0], args[1]
+			store := cfg.GetStore(oldAlias)
+			if store == nil {
+				return fmt.Errorf("store with alias %q not found", oldAlias)
+			}
+			if cfg.GetStore(newAlias) != nil {
+				return fmt.Errorf("store with alias %q already exists", newAlias)
+			}
+
+			if !yes {
+				ok, err := confirm(fmt.Sprintf("Rename store %s to %s", oldAlias, newAlias))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					fmt.Fprintln(cmd.OutOrStdout(), "Aborted")
+					return nil
+				}
+			}
+
+			updated := *store
+			updated.Alias = newAlias
+			if err := cfg.UpdateStore(oldAlias, updated); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Store %s renamed to %s\n", oldAlias, newAlias)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation prompt")
+	return cmd
+}
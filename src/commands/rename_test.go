@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/manifoldco/promptui"
+)
+
+func TestRenameCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		confirmErr error
+		wantErr    bool
+		errMsg     string
+		wantAlias  string
+	}{
+		{
+			name:      "rename after confirmation",
+			args:      []string{"rename", "test-alias", "new-alias"},
+			wantAlias: "new-alias",
+		},
+		{
+			name:      "rename without prompting",
+			args:      []string{"rename", "test-alias", "new-alias", "-y"},
+			wantAlias: "new-alias",
+		},
+		{
+			name:       "confirmation declined",
+			args:       []string{"rename", "test-alias", "new-alias"},
+			confirmErr: promptui.ErrAbort,
+			wantAlias:  "test-alias",
+		},
+		{
+			name:    "old alias not found",
+			args:    []string{"rename", "invalid-store", "new-alias"},
+			wantErr: true,
+			errMsg:  "store with alias \"invalid-store\" not found",
+		},
+		{
+			name:    "new alias already exists",
+			args:    []string{"rename", "test-alias", "other-alias"},
+			wantErr: true,
+			errMsg:  "store with alias \"other-alias\" already exists",
+		},
+		{
+			name:    "missing new alias",
+			args:    []string{"rename", "test-alias"},
+			wantErr: true,
+			errMsg:  "accepts 2 arg(s), received 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t)
+			h.mock.AddStore("test-store", "test-alias", "test-dir")
+			h.mock.AddStore("other-store", "other-alias", "other-dir")
+
+			cleanup := MockPrompt(func(p promptui.Prompt) (string, error) {
+				if tt.confirmErr != nil {
+					return "", tt.confirmErr
+				}
+				return "y", nil
+			})
+			defer cleanup()
+
+			cmd := NewRenameCommand(h.mock)
+			h.setupCommand(cmd)
+
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			store := h.mock.GetStore(tt.wantAlias)
+			if store == nil {
+				t.Fatalf("store with alias %q not found after rename", tt.wantAlias)
+			}
+			if store.StoreID != "test-store" || store.ProjectDir != "test-dir" {
+				t.Errorf("store = %+v, want store ID and project dir preserved", store)
+			}
+		})
+	}
+}
@@ -1,24 +1,91 @@
 package commands
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/colinxr/shopify-theme-manager/shopify"
 	"github.com/spf13/cobra"
 )
 
-func NewRootCommand(cfg config.Manager) *cobra.Command {
+// configFlag names the persistent flag that overrides the config location
+const configFlag = "config"
+
+// worksWithoutConfig is the annotation of commands that still run when the
+// config file can't be read, because they diagnose it or don't use it.
+const worksWithoutConfig = "worksWithoutConfig"
+
+func NewRootCommand(cfg config.Manager, runner shopify.Runner) *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:     "stm",
 		Version: "0.0.9",
 		Short:   "Shopify Theme Manager - A CLI tool to manage Shopify themes",
 	}
 
+	// The config file is opened before the command line is parsed, so the
+	// flag's value is read with ConfigPathFromArgs. It is declared here so
+	// cobra accepts it and lists it in help output.
+	rootCmd.PersistentFlags().String(configFlag, "", "Path to the config file (overrides $"+config.ConfigEnvVar+")")
+
 	// Add commands
 	rootCmd.AddCommand(
 		NewAddCommand(cfg),
-		NewListCommand(cfg),
-		NewDevCommand(cfg),
+		NewScanCommand(cfg),
+		NewEditCommand(cfg),
+		NewRenameCommand(cfg),
+		NewRemoveCommand(cfg),
+		NewStoresCommand(cfg),
+		NewUseCommand(cfg),
+		NewStatusCommand(cfg),
+		NewTagCommand(cfg),
+		NewListCommand(cfg, runner),
+		NewThemeCommand(cfg),
+		NewProtectCommand(cfg),
+		NewUnprotectCommand(cfg),
+		NewDevCommand(cfg, runner),
+		NewPushCommand(cfg, runner),
+		NewPullCommand(cfg, runner),
+		NewIgnoreCommand(cfg),
+		NewEnvCommand(cfg),
+		NewEachCommand(cfg, runner),
+		NewCdCommand(cfg),
+		NewShellInitCommand(),
+		NewCompletionCommand(),
 		NewSetWorkspaceCommand(cfg),
+		NewConfigCommand(cfg),
+		NewDoctorCommand(cfg, runner),
 	)
 
 	return rootCmd
 }
+
+// RequireConfig makes every command except those annotated with
+// worksWithoutConfig fail with err, the reason the config file could not be
+// loaded.
+func RequireConfig(rootCmd *cobra.Command, err error) {
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if cmd.Annotations[worksWithoutConfig] != "" || cmd.Name() == "help" {
+			return nil
+		}
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%w; run stm doctor for help", err)
+	}
+}
+
+// ConfigPathFromArgs returns the value of --config from raw command line
+// arguments, or an empty string if it was not given.
+func ConfigPathFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if value, ok := strings.CutPrefix(arg, "--"+configFlag+"="); ok {
+			return value
+		}
+		if arg == "--"+configFlag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
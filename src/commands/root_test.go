@@ -0,0 +1,41 @@
+package commands
+
+import "testing"
+
+func TestConfigPathFromArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "not set", args: []string{"list", "store1"}, want: ""},
+		{name: "separate value", args: []string{"--config", "/tmp/stm.json", "stores"}, want: "/tmp/stm.json"},
+		{name: "equals value", args: []string{"stores", "--config=/tmp/stm.json"}, want: "/tmp/stm.json"},
+		{name: "missing value", args: []string{"stores", "--config"}, want: ""},
+		{name: "after terminator", args: []string{"each", "--", "--config", "/tmp/stm.json"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConfigPathFromArgs(tt.args); got != tt.want {
+				t.Errorf("ConfigPathFromArgs(%v) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRootCommand_AcceptsConfigFlag(t *testing.T) {
+	h := newTestHelper(t)
+	h.mock.(*MockConfig).configPath = "/tmp/stm.json"
+
+	rootCmd := NewRootCommand(h.mock, h.runner)
+	rootCmd.SetOut(h.output)
+	rootCmd.SetArgs([]string{"--config", "/tmp/stm.json", "config", "path"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := h.output.String(); got != "/tmp/stm.json\n" {
+		t.Errorf("output = %q, want config path", got)
+	}
+}
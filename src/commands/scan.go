@@ -0,0 +1,258 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/colinxr/shopify-theme-manager/shopify"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+// defaultScanDepth is how many directory levels below the workspace stm
+// scan searches for themes
+const defaultScanDepth = 3
+
+// scanResult is a theme directory found by stm scan
+type scanResult struct {
+	dir string // relative to the workspace
+	// registeredAs is the alias of the store whose project directory
+	// contains dir, if any
+	registeredAs string
+	// stores are the registered store's ID, or the store IDs named in the
+	// directory's shopify.theme.toml
+	stores []string
+}
+
+func NewScanCommand(cfg config.Manager) *cobra.Command {
+	var (
+		yes    bool
+		dryRun bool
+		depth  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Find theme projects in the workspace and register them",
+		Long: `Search the workspace for Shopify theme directories: directories with a
+layout/theme.liquid, a config/settings_schema.json or a shopify.theme.toml.
+
+For each one that isn't inside a configured store's project directory,
+stm asks whether to register it, suggesting the store from the
+shopify.theme.toml environments and the directory name as alias.
+
+With --yes, directories whose store is known from shopify.theme.toml are
+registered without asking and the rest are skipped.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workspace := cfg.GetWorkspace()
+			if workspace == "" {
+				return fmt.Errorf("no workspace set; run: stm set-workspace <dir>")
+			}
+			if depth < 1 {
+				return fmt.Errorf("--depth must be at least 1")
+			}
+			if !dryRun && !yes && !stdinIsTerminal() {
+				return fmt.Errorf("stm scan asks before registering each directory; pass --yes or --dry-run when not running interactively")
+			}
+
+			results, err := scanWorkspace(cmd, cfg, workspace, depth)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(results) == 0 {
+				fmt.Fprintf(out, "No theme directories found in %s\n", workspace)
+				return nil
+			}
+			if dryRun {
+				return printScanResults(cmd, results)
+			}
+
+			found := unregistered(results)
+			if len(found) == 0 {
+				fmt.Fprintf(out, "No new theme directories; %d already registered\n", len(results))
+				return nil
+			}
+
+			registered := 0
+			for _, result := range found {
+				ok, err := registerScanResult(cmd, cfg, result, yes)
+				if err != nil {
+					return err
+				}
+				if ok {
+					registered++
+				}
+			}
+			fmt.Fprintf(out, "Registered %d of %d new theme directories\n", registered, len(found))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Register directories with a known store without asking")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List theme directories without registering them")
+	cmd.Flags().IntVar(&depth, "depth", defaultScanDepth, "Number of directory levels to search")
+	return cmd
+}
+
+// scanWorkspace finds the theme directories in the workspace and matches
+// them against the configured stores.
+func scanWorkspace(cmd *cobra.Command, cfg config.Manager, workspace string, depth int) ([]scanResult, error) {
+	dirs, err := shopify.FindThemeDirs(workspace, depth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan workspace: %w", err)
+	}
+
+	stores := cfg.ListStores()
+	results := make([]scanResult, 0, len(dirs))
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(workspace, dir)
+		if err != nil {
+			rel = dir
+		}
+		result := scanResult{dir: rel}
+
+		if matches := config.StoresForDir(stores, workspace, dir); len(matches) > 0 {
+			result.registeredAs = matches[0].Alias
+			result.stores = []string{matches[0].StoreID}
+			results = append(results, result)
+			continue
+		}
+
+		envs, err := shopify.ReadThemeConfig(dir)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s: %v\n", rel, err)
+		}
+		result.stores = envStores(envs)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// envStores returns the distinct store IDs of the environments, with the
+// production environment's store first.
+func envStores(envs []shopify.Environment) []string {
+	var stores []string
+	add := func(env shopify.Environment) {
+		storeID, err := config.NormalizeStoreID(env.Store)
+		if err == nil && !containsString(stores, storeID) {
+			stores = append(stores, storeID)
+		}
+	}
+	for _, env := range envs {
+		if env.Name == "production" {
+			add(env)
+		}
+	}
+	for _, env := range envs {
+		add(env)
+	}
+	return stores
+}
+
+func unregistered(results []scanResult) []scanResult {
+	var found []scanResult
+	for _, result := range results {
+		if result.registeredAs == "" {
+			found = append(found, result)
+		}
+	}
+	return found
+}
+
+func printScanResults(cmd *cobra.Command, results []scanResult) error {
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DIRECTORY\tSTORE\tSTATUS")
+	for _, result := range results {
+		status := "not registered"
+		store := strings.Join(result.stores, ",")
+		if result.registeredAs != "" {
+			status = "registered as " + result.registeredAs
+		}
+		if store == "" {
+			store = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", result.dir, store, status)
+	}
+	return tw.Flush()
+}
+
+// registerScanResult registers one theme directory, asking first unless
+// yes is set. It reports whether the directory was registered.
+func registerScanResult(cmd *cobra.Command, cfg config.Manager, result scanResult, yes bool) (bool, error) {
+	out := cmd.OutOrStdout()
+	alias := suggestAlias(result.dir)
+
+	if yes {
+		if len(result.stores) == 0 {
+			fmt.Fprintf(out, "Skipped %s: no store in %s\n", result.dir, shopify.ThemeConfigFile)
+			return false, nil
+		}
+		storeID := result.stores[0]
+		if err := storeIDValidator(cfg)(storeID); err != nil {
+			fmt.Fprintf(out, "Skipped %s: %v\n", result.dir, err)
+			return false, nil
+		}
+		if err := aliasValidator(cfg)(alias); err != nil || alias == "" {
+			fmt.Fprintf(out, "Skipped %s: no free alias; register it with stm add\n", result.dir)
+			return false, nil
+		}
+		return addScannedStore(cmd, cfg, storeID, alias, result.dir)
+	}
+
+	ok, err := confirm(fmt.Sprintf("Register %s", result.dir))
+	if err != nil || !ok {
+		return false, err
+	}
+
+	storePrompt := promptui.Prompt{
+		Label:    "Enter the Shopify store ID",
+		Validate: storeIDValidator(cfg),
+	}
+	if len(result.stores) > 0 {
+		storePrompt.Default = result.stores[0]
+	}
+	storeID, err := runPrompt(storePrompt)
+	if err != nil {
+		return false, err
+	}
+
+	aliasPrompt := promptui.Prompt{
+		Label:    "Enter an alias for the store (optional)",
+		Default:  alias,
+		Validate: aliasValidator(cfg),
+	}
+	alias, err = runPrompt(aliasPrompt)
+	if err != nil {
+		return false, err
+	}
+	if alias == "" {
+		alias = storeID
+	}
+	return addScannedStore(cmd, cfg, storeID, alias, result.dir)
+}
+
+func addScannedStore(cmd *cobra.Command, cfg config.Manager, storeID, alias, dir string) (bool, error) {
+	if err := cfg.AddStore(storeID, alias, dir); err != nil {
+		return false, err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Store %s added for %s\n", alias, dir)
+	return true, nil
+}
+
+// suggestAlias turns a directory's name into an alias, replacing the
+// characters aliases don't allow with '-'.
+func suggestAlias(dir string) string {
+	alias := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(filepath.Base(dir)))
+	return strings.TrimLeft(alias, "-.")
+}
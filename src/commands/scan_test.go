@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/manifoldco/promptui"
+)
+
+// setupScanWorkspace creates a workspace with an unregistered theme that
+// names its store, an unregistered theme that doesn't, and a registered one.
+func setupScanWorkspace(t *testing.T, h *testHelper) string {
+	t.Helper()
+	workspace := t.TempDir()
+	files := map[string]string{
+		"acme/layout/theme.liquid":            "",
+		"acme/shopify.theme.toml":             "[environments.staging]\nstore = \"acme-staging\"\n\n[environments.production]\nstore = \"acme\"\n",
+		"globex/config/settings_schema.json":  "[]",
+		"clients/initech/layout/theme.liquid": "",
+		"clients/initech/shopify.theme.toml":  "[environments.production]\nstore = \"initech\"\n",
+		"notes/README.md":                     "",
+	}
+	for name, content := range files {
+		path := filepath.Join(workspace, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.mock.SetWorkspace(workspace)
+	h.mock.AddStore("initech.myshopify.com", "initech", "clients/initech")
+	return workspace
+}
+
+func TestScanCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		isTerminal bool
+		// answers maps prompt labels to responses; an empty response
+		// accepts the default and "n" declines a confirmation
+		answers  map[string]string
+		wantErr  bool
+		errMsg   string
+		contains []string
+		want     map[string]string // alias -> store ID
+		notWant  []string
+	}{
+		{
+			name:     "dry run",
+			args:     []string{"scan", "--dry-run"},
+			contains: []string{"acme.myshopify.com,acme-staging.myshopify.com", "not registered", "globex", "registered as initech"},
+			notWant:  []string{"acme", "globex"},
+		},
+		{
+			name:     "yes registers stores known from shopify.theme.toml",
+			args:     []string{"scan", "--yes"},
+			contains: []string{"Store acme added for acme", "Skipped globex: no store in shopify.theme.toml", "Registered 1 of 2 new theme directories"},
+			want:     map[string]string{"acme": "acme.myshopify.com"},
+			notWant:  []string{"globex"},
+		},
+		{
+			name:       "interactive",
+			args:       []string{"scan"},
+			isTerminal: true,
+			answers: map[string]string{
+				"Register acme":                           "",
+				"Enter the Shopify store ID":              "",
+				"Enter an alias for the store (optional)": "",
+				"Register globex":                         "n",
+			},
+			contains: []string{"Registered 1 of 2 new theme directories"},
+			want:     map[string]string{"acme": "acme.myshopify.com"},
+			notWant:  []string{"globex"},
+		},
+		{
+			name:       "interactive with answers",
+			args:       []string{"scan"},
+			isTerminal: true,
+			answers: map[string]string{
+				"Register acme":                           "n",
+				"Register globex":                         "",
+				"Enter the Shopify store ID":              "globex-shop",
+				"Enter an alias for the store (optional)": "gx",
+			},
+			want:    map[string]string{"gx": "globex-shop"},
+			notWant: []string{"acme", "globex"},
+		},
+		{
+			name:    "not interactive",
+			args:    []string{"scan"},
+			wantErr: true,
+			errMsg:  "pass --yes or --dry-run",
+		},
+		{
+			name:    "invalid depth",
+			args:    []string{"scan", "--dry-run", "--depth", "0"},
+			wantErr: true,
+			errMsg:  "--depth must be at least 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer MockStdinIsTerminal(tt.isTerminal)()
+			defer MockPrompt(func(p promptui.Prompt) (string, error) {
+				label := p.Label.(string)
+				answer, ok := tt.answers[label]
+				if !ok {
+					t.Errorf("unexpected prompt %q", label)
+					return "", promptui.ErrInterrupt
+				}
+				if p.IsConfirm {
+					if answer == "n" {
+						return "", promptui.ErrAbort
+					}
+					return "y", nil
+				}
+				if answer == "" {
+					answer = p.Default
+				}
+				if err := p.Validate(answer); err != nil {
+					return "", err
+				}
+				return answer, nil
+			})()
+
+			h := newTestHelper(t)
+			setupScanWorkspace(t, h)
+			h.setupCommand(NewScanCommand(h.mock))
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			output := h.output.String()
+			for _, want := range tt.contains {
+				if !strings.Contains(output, want) {
+					t.Errorf("output missing %q:\n%s", want, output)
+				}
+			}
+			for alias, storeID := range tt.want {
+				store := h.mock.GetStore(alias)
+				if store == nil {
+					t.Errorf("store %q was not added", alias)
+					continue
+				}
+				if store.StoreID != storeID {
+					t.Errorf("store %q ID = %s, want %s", alias, store.StoreID, storeID)
+				}
+			}
+			for _, alias := range tt.notWant {
+				if _, ok := tt.want[alias]; !ok && h.mock.GetStore(alias) != nil {
+					t.Errorf("store %q was added", alias)
+				}
+			}
+		})
+	}
+}
+
+func TestScanCommand_NoWorkspace(t *testing.T) {
+	h := newTestHelper(t)
+	h.setupCommand(NewScanCommand(h.mock))
+	h.cmd.SetArgs([]string{"scan", "--dry-run"})
+	if err := h.cmd.Execute(); err == nil || !strings.Contains(err.Error(), "no workspace set") {
+		t.Errorf("error = %v, want no workspace error", err)
+	}
+}
+
+func TestSuggestAlias(t *testing.T) {
+	tests := map[string]string{
+		"acme":              "acme",
+		"clients/Acme Shop": "acme-shop",
+		".hidden":           "hidden",
+		".":                 "",
+	}
+	for dir, want := range tests {
+		if got := suggestAlias(dir); got != want {
+			t.Errorf("suggestAlias(%q) = %q, want %q", dir, got, want)
+		}
+	}
+}
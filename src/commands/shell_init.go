@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// Shell wrappers installed by "stm shell-init". Each defines an stm function
+// that changes directory for "stm cd [alias]" and defers everything else to
+// the stm binary, and registers completion through cobra's __complete
+// command.
+var shellInitScripts = map[string]string{
+	"bash": `stm() {
+  if [ "$1" = "cd" ] && [ "$#" -le 2 ] && [ "${2#-}" = "$2" ]; then
+    shift
+    local dir
+    dir="$(command stm cd "$@")" || return $?
+    builtin cd -- "$dir"
+  else
+    command stm "$@"
+  fi
+}
+
+_stm_complete() {
+  local IFS=$'\n'
+  COMPREPLY=($(command stm __complete "${COMP_WORDS[@]:1:$COMP_CWORD}" 2>/dev/null | grep -v '^:' | cut -f1))
+}
+complete -o default -F _stm_complete stm
+`,
+	"zsh": `stm() {
+  if [ "$1" = "cd" ] && [ "$#" -le 2 ] && [ "${2#-}" = "$2" ]; then
+    shift
+    local dir
+    dir="$(command stm cd "$@")" || return $?
+    builtin cd -- "$dir"
+  else
+    command stm "$@"
+  fi
+}
+
+_stm_complete() {
+  local -a completions
+  completions=("${(@f)$(command stm __complete "${(@)words[2,CURRENT]}" 2>/dev/null | grep -v '^:' | cut -f1)}")
+  compadd -a completions
+}
+(( $+functions[compdef] )) && compdef _stm_complete stm
+`,
+	"fish": `function stm
+    if test "$argv[1]" = cd; and test (count $argv) -le 2; and not string match -q -- '-*' "$argv[2]"
+        set -e argv[1]
+        set -l dir (command stm cd $argv); or return $status
+        builtin cd $dir
+    else
+        command stm $argv
+    end
+end
+
+complete -c stm -f -a '(command stm __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null | string match -v ":*" | string replace -r "\t.*" "")'
+`,
+}
+
+func NewShellInitCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell-init <bash|zsh|fish>",
+		Short: "Print shell integration for stm cd and alias completion",
+		Long: `Print a shell function that wraps stm so "stm cd <alias>" changes the
+current directory, along with tab completion for store aliases.
+
+Add one of these to your shell startup file:
+
+  eval "$(stm shell-init bash)"   # ~/.bashrc
+  eval "$(stm shell-init zsh)"    # ~/.zshrc
+  stm shell-init fish | source    # ~/.config/fish/config.fish`,
+		Args:        cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs:   []string{"bash", "zsh", "fish"},
+		Annotations: map[string]string{worksWithoutConfig: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := fmt.Fprint(cmd.OutOrStdout(), shellInitScripts[args[0]])
+			return err
+		},
+	}
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestShellInitCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantOutput []string
+		wantErr    bool
+		errMsg     string
+	}{
+		{
+			name:       "bash",
+			args:       []string{"shell-init", "bash"},
+			wantOutput: []string{"stm() {", `command stm cd "$@"`, "builtin cd", "complete -o default -F _stm_complete stm"},
+		},
+		{
+			name:       "zsh",
+			args:       []string{"shell-init", "zsh"},
+			wantOutput: []string{"stm() {", "builtin cd", "compdef _stm_complete stm"},
+		},
+		{
+			name:       "fish",
+			args:       []string{"shell-init", "fish"},
+			wantOutput: []string{"function stm", "builtin cd $dir", "complete -c stm"},
+		},
+		{
+			name:    "unsupported shell",
+			args:    []string{"shell-init", "tcsh"},
+			wantErr: true,
+			errMsg:  "invalid argument \"tcsh\"",
+		},
+		{
+			name:    "missing shell",
+			args:    []string{"shell-init"},
+			wantErr: true,
+			errMsg:  "accepts 1 arg(s), received 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t)
+
+			cmd := NewShellInitCommand()
+			h.setupCommand(cmd)
+
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(h.output.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, h.output.String())
+				}
+			}
+		})
+	}
+}
+
+// TestShellInitScripts_ChangeDirectory sources each wrapper in its shell,
+// with a fake stm on PATH, and checks that "stm cd" moves with and without
+// an alias. Shells that aren't installed are skipped.
+func TestShellInitScripts_ChangeDirectory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as stm")
+	}
+
+	root, _ := filepath.EvalSymlinks(t.TempDir())
+	for _, dir := range []string{"bin", "current", "store1"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The fake stm prints the current store's directory when no alias is given
+	fake := "#!/bin/sh\nif [ \"$1\" = cd ]; then echo \"" + root + "/${2:-current}\"; fi\n"
+	if err := os.WriteFile(filepath.Join(root, "bin", "stm"), []byte(fake), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for shell, script := range shellInitScripts {
+		shell, script := shell, script
+		t.Run(shell, func(t *testing.T) {
+			path, err := exec.LookPath(shell)
+			if err != nil {
+				t.Skipf("%s is not installed", shell)
+			}
+
+			file := filepath.Join(t.TempDir(), "test."+shell)
+			test := script + "\nstm cd\npwd\nstm cd store1\npwd\n"
+			if err := os.WriteFile(file, []byte(test), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := exec.Command(path, file)
+			cmd.Dir = root
+			cmd.Env = append(os.Environ(), "PATH="+filepath.Join(root, "bin")+string(os.PathListSeparator)+os.Getenv("PATH"))
+			out, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("%s failed: %v\n%s", shell, err, out)
+			}
+
+			want := filepath.Join(root, "current") + "\n" + filepath.Join(root, "store1") + "\n"
+			if string(out) != want {
+				t.Errorf("%s printed %q, want %q", shell, out, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/spf13/cobra"
+)
+
+// statusInfo is the rendered form of stm status
+type statusInfo struct {
+	ConfigPath   string            `json:"configPath" yaml:"configPath"`
+	Workspace    string            `json:"workspace" yaml:"workspace"`
+	CurrentStore *currentStoreInfo `json:"currentStore" yaml:"currentStore"`
+	// Error explains why the current store could not be worked out
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type currentStoreInfo struct {
+	Alias string `json:"alias" yaml:"alias"`
+	// Source is where the current store was selected: STM_STORE, current
+	// directory or stm use
+	Source     string `json:"source" yaml:"source"`
+	Found      bool   `json:"found" yaml:"found"`
+	StoreID    string `json:"storeId,omitempty" yaml:"storeId,omitempty"`
+	ProjectDir string `json:"projectDir,omitempty" yaml:"projectDir,omitempty"`
+	Protected  bool   `json:"protected,omitempty" yaml:"protected,omitempty"`
+}
+
+func NewStatusCommand(cfg config.Manager) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the current store and config",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status := statusInfo{
+				ConfigPath: cfg.ConfigPath(),
+				Workspace:  cfg.GetWorkspace(),
+			}
+
+			alias, source, err := currentStore(cfg)
+			if err != nil {
+				status.Error = err.Error()
+			}
+			if alias != "" {
+				current := &currentStoreInfo{Alias: alias, Source: source}
+				if store := cfg.GetStore(alias); store != nil {
+					current.Found = true
+					current.StoreID = store.StoreID
+					current.ProjectDir = store.ProjectPath(status.Workspace)
+					current.Protected = store.Protected
+				}
+				status.CurrentStore = current
+			}
+
+			return renderOutput(cmd.OutOrStdout(), output, status, func(tw *tabwriter.Writer) {
+				current := status.CurrentStore
+				switch {
+				case status.Error != "":
+					fmt.Fprintf(tw, "Current store:\t%s\n", status.Error)
+				case current == nil:
+					fmt.Fprintln(tw, "Current store:\tnone (set one with: stm use <store-alias>)")
+				case !current.Found:
+					fmt.Fprintf(tw, "Current store:\t%s (from %s, not configured)\n", current.Alias, current.Source)
+				default:
+					fmt.Fprintf(tw, "Current store:\t%s (from %s)\n", current.Alias, current.Source)
+					fmt.Fprintf(tw, "Store ID:\t%s\n", current.StoreID)
+					fmt.Fprintf(tw, "Project dir:\t%s\n", current.ProjectDir)
+					if current.Protected {
+						fmt.Fprintln(tw, "Protected:\tyes")
+					}
+				}
+
+				workspace := status.Workspace
+				if workspace == "" {
+					workspace = "not set"
+				}
+				fmt.Fprintf(tw, "Workspace:\t%s\n", workspace)
+				fmt.Fprintf(tw, "Config:\t%s\n", status.ConfigPath)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", outputTable, "Output format (table, json, yaml)")
+	return cmd
+}
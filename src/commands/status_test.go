@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStatusCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		current  string
+		env      string
+		dir      string
+		protect  bool
+		contains []string
+	}{
+		{
+			name:     "no current store",
+			args:     []string{"status"},
+			contains: []string{"Current store:  none", "Workspace:      /workspace"},
+		},
+		{
+			name:     "current store",
+			args:     []string{"status"},
+			current:  "alpha",
+			protect:  true,
+			contains: []string{"Current store:  alpha (from stm use)", "Store ID:       alpha-store", "Project dir:    /workspace/alpha-dir", "Protected:      yes"},
+		},
+		{
+			name:     "store from working directory",
+			args:     []string{"status"},
+			dir:      "/workspace/alpha-dir/templates",
+			contains: []string{"Current store:  alpha (from current directory)"},
+		},
+		{
+			name:     "unknown store from environment",
+			args:     []string{"status"},
+			env:      "gamma",
+			contains: []string{"Current store:  gamma (from STM_STORE, not configured)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := tt.dir
+			if dir == "" {
+				dir = "/elsewhere"
+			}
+			defer MockStoreEnv(tt.env)()
+			defer MockWorkingDir(dir)()
+			h := newTestHelper(t)
+			h.mock.SetWorkspace("/workspace")
+			h.mock.AddStore("alpha-store", "alpha", "alpha-dir")
+			h.mock.SetCurrentStore(tt.current)
+			if tt.protect {
+				store := *h.mock.GetStore("alpha")
+				store.Protected = true
+				h.mock.UpdateStore("alpha", store)
+			}
+
+			h.setupCommand(NewStatusCommand(h.mock))
+			h.cmd.SetArgs(tt.args)
+			if err := h.cmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.contains {
+				if !strings.Contains(h.output.String(), want) {
+					t.Errorf("output = %q, want it to contain %q", h.output.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestStatusCommand_JSON(t *testing.T) {
+	defer MockStoreEnv("")()
+	h := newTestHelper(t)
+	h.mock.AddStore("alpha-store", "alpha", "/projects/alpha")
+	h.mock.SetCurrentStore("alpha")
+
+	h.setupCommand(NewStatusCommand(h.mock))
+	h.cmd.SetArgs([]string{"status", "-o", "json"})
+	if err := h.cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var status statusInfo
+	if err := json.Unmarshal(h.output.Bytes(), &status); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if status.CurrentStore == nil || status.CurrentStore.Alias != "alpha" || !status.CurrentStore.Found || status.CurrentStore.ProjectDir != "/projects/alpha" {
+		t.Errorf("currentStore = %+v", status.CurrentStore)
+	}
+}
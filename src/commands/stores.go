@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/spf13/cobra"
+)
+
+// storeInfo is the rendered form of a configured store
+type storeInfo struct {
+	Alias      string `json:"alias" yaml:"alias"`
+	StoreID    string `json:"storeId" yaml:"storeId"`
+	ProjectDir string `json:"projectDir" yaml:"projectDir"`
+	DirExists  bool   `json:"dirExists" yaml:"dirExists"`
+
+	Themes    map[string]string `json:"themes,omitempty" yaml:"themes,omitempty"`
+	Protected bool              `json:"protected,omitempty" yaml:"protected,omitempty"`
+	Ignore    []string          `json:"ignore,omitempty" yaml:"ignore,omitempty"`
+	Tags      []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+func NewStoresCommand(cfg config.Manager) *cobra.Command {
+	var (
+		output string
+		tags   []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stores",
+		Short: "List all configured stores",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workspace := cfg.GetWorkspace()
+			stores, err := selectStores(cfg.ListStores(), tags)
+			if err != nil {
+				return err
+			}
+
+			infos := make([]storeInfo, 0)
+			for _, store := range stores {
+				dir := store.ProjectPath(workspace)
+				info, err := os.Stat(dir)
+				infos = append(infos, storeInfo{
+					Alias:      store.Alias,
+					StoreID:    store.StoreID,
+					ProjectDir: dir,
+					DirExists:  err == nil && info.IsDir(),
+					Themes:     store.Themes,
+					Protected:  store.Protected,
+					Ignore:     store.Ignore,
+					Tags:       store.Tags,
+				})
+			}
+
+			return renderOutput(cmd.OutOrStdout(), output, infos, func(tw *tabwriter.Writer) {
+				fmt.Fprintln(tw, "ALIAS\tSTORE ID\tPROJECT DIR\tTAGS\tEXISTS")
+				for _, info := range infos {
+					exists := "no"
+					if info.DirExists {
+						exists = "yes"
+					}
+					tagList := "-"
+					if len(info.Tags) > 0 {
+						tagList = strings.Join(info.Tags, ",")
+					}
+					fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", info.Alias, info.StoreID, info.ProjectDir, tagList, exists)
+				}
+			})
+		},
+	}
+
+	addTagFlag(cmd, cfg, &tags)
+	cmd.Flags().StringVarP(&output, "output", "o", outputTable, "Output format (table, json, yaml)")
+	return cmd
+}
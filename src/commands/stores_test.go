@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestStoresCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+		errMsg  string
+		verify  func(t *testing.T, output, workspace string)
+	}{
+		{
+			name: "table output",
+			args: []string{"stores"},
+			verify: func(t *testing.T, output, workspace string) {
+				lines := strings.Split(strings.TrimSpace(output), "\n")
+				if len(lines) != 3 {
+					t.Fatalf("got %d lines, want 3:\n%s", len(lines), output)
+				}
+				if !strings.HasPrefix(lines[0], "ALIAS") {
+					t.Errorf("header = %q, want ALIAS column first", lines[0])
+				}
+				wantDir := filepath.Join(workspace, "alpha-dir")
+				if !strings.Contains(lines[1], "alpha") || !strings.Contains(lines[1], wantDir) || !strings.HasSuffix(lines[1], "yes") {
+					t.Errorf("row = %q, want alpha with existing %s", lines[1], wantDir)
+				}
+				if !strings.Contains(lines[2], "beta") || !strings.HasSuffix(lines[2], "no") {
+					t.Errorf("row = %q, want beta with missing directory", lines[2])
+				}
+				// Columns are aligned
+				if strings.Index(lines[1], "alpha-store") != strings.Index(lines[2], "beta-store") {
+					t.Errorf("store ID column is not aligned:\n%s", output)
+				}
+			},
+		},
+		{
+			name: "json output",
+			args: []string{"stores", "--output", "json"},
+			verify: func(t *testing.T, output, workspace string) {
+				var infos []storeInfo
+				if err := json.Unmarshal([]byte(output), &infos); err != nil {
+					t.Fatalf("invalid JSON output: %v", err)
+				}
+				if len(infos) != 2 {
+					t.Fatalf("got %d stores, want 2", len(infos))
+				}
+				if infos[0].Alias != "alpha" || infos[0].StoreID != "alpha-store" || !infos[0].DirExists {
+					t.Errorf("stores[0] = %+v", infos[0])
+				}
+				if infos[1].DirExists {
+					t.Errorf("stores[1].DirExists = true, want false")
+				}
+			},
+		},
+		{
+			name: "yaml output",
+			args: []string{"stores", "-o", "yaml"},
+			verify: func(t *testing.T, output, workspace string) {
+				var infos []storeInfo
+				if err := yaml.Unmarshal([]byte(output), &infos); err != nil {
+					t.Fatalf("invalid YAML output: %v", err)
+				}
+				if len(infos) != 2 || infos[1].Alias != "beta" {
+					t.Errorf("stores = %+v", infos)
+				}
+			},
+		},
+		{
+			name:    "unsupported output",
+			args:    []string{"stores", "--output", "xml"},
+			wantErr: true,
+			errMsg:  "unsupported output format \"xml\"",
+		},
+		{
+			name: "filter by tag expression",
+			args: []string{"stores", "--tag", "client:acme & !tier:plus", "-o", "json"},
+			verify: func(t *testing.T, output, workspace string) {
+				var infos []storeInfo
+				if err := json.Unmarshal([]byte(output), &infos); err != nil {
+					t.Fatalf("invalid JSON output: %v", err)
+				}
+				if len(infos) != 1 || infos[0].Alias != "beta" {
+					t.Errorf("stores = %+v, want only beta", infos)
+				}
+				if !reflect.DeepEqual(infos[0].Tags, []string{"client:acme"}) {
+					t.Errorf("tags = %v, want [client:acme]", infos[0].Tags)
+				}
+			},
+		},
+		{
+			name: "tags column",
+			args: []string{"stores", "--tag", "tier:plus"},
+			verify: func(t *testing.T, output, workspace string) {
+				lines := strings.Split(strings.TrimSpace(output), "\n")
+				if len(lines) != 2 {
+					t.Fatalf("got %d lines, want 2:\n%s", len(lines), output)
+				}
+				if !strings.Contains(lines[0], "TAGS") || !strings.Contains(lines[1], "client:acme,tier:plus") {
+					t.Errorf("table = %q, want alpha's tags", output)
+				}
+			},
+		},
+		{
+			name:    "invalid tag expression",
+			args:    []string{"stores", "--tag", "client:acme &"},
+			wantErr: true,
+			errMsg:  "invalid tag expression \"client:acme &\"",
+		},
+		{
+			name:    "unexpected argument",
+			args:    []string{"stores", "extra"},
+			wantErr: true,
+			errMsg:  "unknown command \"extra\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t)
+
+			workspace := t.TempDir()
+			if err := os.Mkdir(filepath.Join(workspace, "alpha-dir"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			h.mock.SetWorkspace(workspace)
+			h.mock.AddStore("alpha-store", "alpha", "alpha-dir")
+			h.mock.AddStore("beta-store", "beta", "beta-dir")
+			h.mock.AddTags("alpha", "client:acme", "tier:plus")
+			h.mock.AddTags("beta", "client:acme")
+
+			cmd := NewStoresCommand(h.mock)
+			h.setupCommand(cmd)
+
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if tt.verify != nil {
+				tt.verify(t, h.output.String(), workspace)
+			}
+		})
+	}
+}
+
+func TestStoresCommand_Empty(t *testing.T) {
+	h := newTestHelper(t)
+
+	cmd := NewStoresCommand(h.mock)
+	h.setupCommand(cmd)
+
+	h.cmd.SetArgs([]string{"stores", "--output", "json"})
+	if err := h.cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := strings.TrimSpace(h.output.String()); got != "[]" {
+		t.Errorf("output = %q, want empty JSON array", got)
+	}
+}
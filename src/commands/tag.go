@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/spf13/cobra"
+)
+
+// tagExprHelp describes the tag expression syntax in flag help
+const tagExprHelp = `Only include stores matching the tag expression, e.g. "client:acme" or
+"base:dawn & !tier:plus" (operators: ! & | , and parentheses). Repeat to
+require every expression`
+
+func NewTagCommand(cfg config.Manager) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Manage store tags",
+		Long: `Manage tags such as client:acme, base:dawn or tier:plus that group stores.
+
+Commands that work on many stores, like stm stores and stm each, select
+stores with --tag <expression>.`,
+	}
+
+	cmd.AddCommand(
+		newTagAddCommand(cfg),
+		newTagRemoveCommand(cfg),
+		newTagListCommand(cfg),
+	)
+	return cmd
+}
+
+func newTagAddCommand(cfg config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "add [store-alias] <tag>...",
+		Short: "Tag a store",
+		Args:  cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeStoreAliases(cfg)(cmd, args, toComplete)
+			}
+			return tagCompletions(cfg, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, tags, err := listStoreArg(cfg, args, "tags")
+			if err != nil {
+				return err
+			}
+			alias := store.Alias
+			if err := cfg.AddTags(alias, tags...); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Store %s tagged %s\n", alias, strings.Join(tags, ", "))
+			return nil
+		},
+	}
+}
+
+func newTagRemoveCommand(cfg config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove [store-alias] <tag>...",
+		Short: "Remove tags from a store",
+		Args:  cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			var tags []string
+			if len(args) == 0 {
+				tags = storeAliasCompletions(cfg, toComplete)
+			}
+			if store := completionStore(cfg, args); store != nil {
+				tags = append(tags, store.Tags...)
+			}
+			return tags, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, tags, err := listStoreArg(cfg, args, "tags")
+			if err != nil {
+				return err
+			}
+			alias := store.Alias
+			if err := cfg.RemoveTags(alias, tags...); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %s from store %s\n", strings.Join(tags, ", "), alias)
+			return nil
+		},
+	}
+}
+
+func newTagListCommand(cfg config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List every tag and the stores that have it",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stores := make(map[string][]string)
+			for _, store := range cfg.ListStores() {
+				for _, tag := range store.Tags {
+					stores[tag] = append(stores[tag], store.Alias)
+				}
+			}
+
+			tags := make([]string, 0, len(stores))
+			for tag := range stores {
+				tags = append(tags, tag)
+			}
+			sort.Strings(tags)
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "TAG\tSTORES")
+			for _, tag := range tags {
+				fmt.Fprintf(tw, "%s\t%s\n", tag, strings.Join(stores[tag], ", "))
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+// addTagFlag registers --tag on a command that works on many stores.
+func addTagFlag(cmd *cobra.Command, cfg config.Manager, exprs *[]string) {
+	cmd.Flags().StringArrayVar(exprs, "tag", nil, tagExprHelp)
+	cmd.RegisterFlagCompletionFunc("tag", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return tagCompletions(cfg, toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// selectStores returns the stores that match every tag expression. No
+// expressions selects every store.
+func selectStores(stores []config.Store, exprs []string) ([]config.Store, error) {
+	parsed := make([]config.TagExpr, 0, len(exprs))
+	for _, expr := range exprs {
+		tagExpr, err := config.ParseTagExpr(expr)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, tagExpr)
+	}
+
+	selected := make([]config.Store, 0, len(stores))
+	for _, store := range stores {
+		matches := true
+		for _, tagExpr := range parsed {
+			if !tagExpr.Match(&store) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			selected = append(selected, store)
+		}
+	}
+	return selected, nil
+}
+
+// tagCompletions returns every tag in use that starts with toComplete
+func tagCompletions(cfg config.Manager, toComplete string) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, store := range cfg.ListStores() {
+		for _, tag := range store.Tags {
+			if !seen[tag] && strings.HasPrefix(tag, toComplete) {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
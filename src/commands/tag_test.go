@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTagCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		errMsg     string
+		wantTags   []string
+		wantOutput string
+	}{
+		{
+			name:       "add tags",
+			args:       []string{"tag", "add", "alpha", "base:dawn", "tier:plus"},
+			wantTags:   []string{"client:acme", "base:dawn", "tier:plus"},
+			wantOutput: "Store alpha tagged base:dawn, tier:plus\n",
+		},
+		{
+			name:    "add invalid tag",
+			args:    []string{"tag", "add", "alpha", "Tier Plus"},
+			wantErr: true,
+			errMsg:  "invalid tag \"Tier Plus\"",
+		},
+		{
+			name:    "add without tag",
+			args:    []string{"tag", "add", "alpha"},
+			wantErr: true,
+			errMsg:  "no tags given for store alpha",
+		},
+		{
+			name:     "remove tag",
+			args:     []string{"tag", "remove", "alpha", "client:acme"},
+			wantTags: nil,
+		},
+		{
+			name:    "remove unknown tag",
+			args:    []string{"tag", "remove", "alpha", "tier:plus"},
+			wantErr: true,
+			errMsg:  "store \"alpha\" has no tag \"tier:plus\"",
+		},
+		{
+			name:       "list tags",
+			args:       []string{"tag", "list"},
+			wantTags:   []string{"client:acme"},
+			wantOutput: "TAG          STORES\nbase:craft   beta\nclient:acme  alpha, beta\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newTestHelper(t)
+			h.mock.AddStore("alpha-store", "alpha", "alpha-dir")
+			h.mock.AddStore("beta-store", "beta", "beta-dir")
+			h.mock.AddTags("alpha", "client:acme")
+			h.mock.AddTags("beta", "client:acme", "base:craft")
+
+			h.setupCommand(NewTagCommand(h.mock))
+
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := h.mock.GetStore("alpha").Tags; !reflect.DeepEqual(got, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", got, tt.wantTags)
+			}
+			if tt.wantOutput != "" && h.output.String() != tt.wantOutput {
+				t.Errorf("output = %q, want %q", h.output.String(), tt.wantOutput)
+			}
+		})
+	}
+}
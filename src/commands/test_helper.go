@@ -2,7 +2,9 @@ package commands
 
 import (
 	"bytes"
+	"context"
 	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/colinxr/shopify-theme-manager/config"
@@ -15,6 +17,7 @@ type testHelper struct {
 	cmd    *cobra.Command
 	output *bytes.Buffer
 	mock   config.Manager
+	runner *MockRunner
 }
 
 func newTestHelper(t *testing.T) *testHelper {
@@ -26,6 +29,7 @@ func newTestHelper(t *testing.T) *testHelper {
 		cmd:    cmd,
 		output: output,
 		mock:   NewMockConfig(),
+		runner: NewMockRunner(),
 	}
 }
 
@@ -36,7 +40,6 @@ func (h *testHelper) setupCommand(cmd *cobra.Command) {
 
 // Reset mocks after tests
 func resetMocks() {
-	execCommand = exec.Command
 	runPrompt = func(p promptui.Prompt) (string, error) {
 		return "", nil
 	}
@@ -51,11 +54,64 @@ func MockPrompt(mockRun func(promptui.Prompt) (string, error)) func() {
 	}
 }
 
-// MockExecCommand replaces exec.Command with a mock
-func MockExecCommand(mockExec func(string, ...string) *exec.Cmd) func() {
-	oldExec := execCommand
-	execCommand = mockExec
+// MockThemeCacheDir points the theme completion cache at dir
+func MockThemeCacheDir(dir string) func() {
+	oldDir := themeCacheDir
+	themeCacheDir = func() (string, error) {
+		return dir, nil
+	}
+	return func() {
+		themeCacheDir = oldDir
+	}
+}
+
+// MockStdinIsTerminal makes commands treat stdin as a terminal or not
+func MockStdinIsTerminal(isTerminal bool) func() {
+	oldIsTerminal := stdinIsTerminal
+	stdinIsTerminal = func() bool {
+		return isTerminal
+	}
+	return func() {
+		stdinIsTerminal = oldIsTerminal
+	}
+}
+
+// MockStoreEnv sets the value commands read from $STM_STORE
+func MockStoreEnv(alias string) func() {
+	oldStoreEnv := storeEnv
+	storeEnv = func() string {
+		return alias
+	}
+	return func() {
+		storeEnv = oldStoreEnv
+	}
+}
+
+// MockWorkingDir makes commands run as if started in dir
+func MockWorkingDir(dir string) func() {
+	oldWorkingDir := workingDir
+	workingDir = func() (string, error) {
+		return dir, nil
+	}
+	return func() {
+		workingDir = oldWorkingDir
+	}
+}
+
+// MockTools makes stm doctor find the named programs on PATH, each printing
+// the given --version output. Other programs are not found.
+func MockTools(versions map[string]string) func() {
+	oldLookPath, oldToolVersion := lookPath, toolVersion
+	lookPath = func(name string) (string, error) {
+		if _, ok := versions[name]; ok {
+			return "/usr/local/bin/" + name, nil
+		}
+		return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+	}
+	toolVersion = func(ctx context.Context, path string) (string, error) {
+		return versions[filepath.Base(path)], nil
+	}
 	return func() {
-		execCommand = oldExec
+		lookPath, toolVersion = oldLookPath, oldToolVersion
 	}
 }
@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/colinxr/shopify-theme-manager/shopify"
+	"github.com/spf13/cobra"
+)
+
+func NewThemeCommand(cfg config.Manager) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "theme",
+		Short: "Manage named theme environments for a store",
+		Long: `Manage named theme environments such as staging or production.
+
+Commands that target a theme accept --env <name> in place of a theme ID.
+Use "live" as the theme ID to always target the published theme.`,
+	}
+
+	cmd.AddCommand(
+		newThemeSetCommand(cfg),
+		newThemeUnsetCommand(cfg),
+	)
+	return cmd
+}
+
+func newThemeSetCommand(cfg config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:               "set [store-alias] <env> <theme-id|live>",
+		Short:             "Map a theme environment to a theme ID",
+		Args:              cobra.RangeArgs(2, 3),
+		ValidArgsFunction: completeStoreAliases(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, rest, err := fixedStoreArg(cfg, args, 2)
+			if err != nil {
+				return err
+			}
+			alias, env, themeID := store.Alias, rest[0], rest[1]
+
+			if err := cfg.SetThemeEnv(alias, env, themeID); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Theme environment %s of store %s set to %s\n", env, alias, themeID)
+			return nil
+		},
+	}
+}
+
+func newThemeUnsetCommand(cfg config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset [store-alias] <env>",
+		Short: "Remove a theme environment",
+		Args:  cobra.RangeArgs(1, 2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch {
+			case len(args) == 0:
+				envs := storeAliasCompletions(cfg, toComplete)
+				if store := completionStore(cfg, nil); store != nil {
+					envs = append(envs, themeEnvCompletions(cfg, store.Alias, toComplete)...)
+				}
+				return envs, cobra.ShellCompDirectiveNoFileComp
+			case len(args) == 1 && cfg.GetStore(args[0]) != nil:
+				return themeEnvCompletions(cfg, args[0], toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, rest, err := fixedStoreArg(cfg, args, 1)
+			if err != nil {
+				return err
+			}
+			alias, env := store.Alias, rest[0]
+
+			if err := cfg.UnsetThemeEnv(alias, env); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Theme environment %s removed from store %s\n", env, alias)
+			return nil
+		},
+	}
+}
+
+// addThemeEnvFlag registers --env on a command whose first argument is a
+// store alias, with completion of that store's environment names.
+func addThemeEnvFlag(cmd *cobra.Command, cfg config.Manager, env *string) {
+	cmd.Flags().StringVarP(env, "env", "e", "", "Named theme environment to use instead of a theme ID")
+	cmd.RegisterFlagCompletionFunc("env", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return themeEnvCompletions(cfg, args[0], toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func themeEnvCompletions(cfg config.Manager, alias, toComplete string) []string {
+	store := cfg.GetStore(alias)
+	if store == nil {
+		return nil
+	}
+
+	var envs []string
+	for env, themeID := range store.Themes {
+		if strings.HasPrefix(env, toComplete) {
+			envs = append(envs, env+"\t"+themeID)
+		}
+	}
+	sort.Strings(envs)
+	return envs
+}
+
+// resolveThemeID returns the theme ID to pass to the Shopify CLI, taken
+// either from an explicit theme argument or from a named environment of
+// the store. LiveTheme is resolved to the ID of the published theme. An
+// empty result means no theme was requested.
+func resolveThemeID(ctx context.Context, runner shopify.Runner, store *config.Store, themeID, env string) (string, error) {
+	if themeID != "" && env != "" {
+		return "", fmt.Errorf("pass either a theme ID or --env, not both")
+	}
+
+	if env != "" {
+		value, ok := store.Themes[env]
+		if !ok {
+			return "", fmt.Errorf("store %q has no theme environment %q; add one with: stm theme set %s %s <theme-id>", store.Alias, env, store.Alias, env)
+		}
+		themeID = value
+	}
+
+	if themeID != config.LiveTheme {
+		return themeID, nil
+	}
+
+	themes, err := shopify.ListThemes(ctx, runner, store.StoreID, shopify.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, theme := range themes {
+		if theme.Role == shopify.RoleLive {
+			return strconv.FormatInt(theme.ID, 10), nil
+		}
+	}
+	return "", fmt.Errorf("store %q has no live theme", store.Alias)
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/colinxr/shopify-theme-manager/shopify"
+)
+
+// themeCacheTTL is how long a cached theme list is used for completion
+// before the Shopify CLI is asked again.
+const themeCacheTTL = 10 * time.Minute
+
+// themeCacheDir is declared at package level for mocking in tests
+var themeCacheDir = func() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "shopify-theme-manager", "themes"), nil
+}
+
+// cachedThemes returns the themes of a store, running
+// "shopify theme list --json" only when the cached copy is missing or
+// older than themeCacheTTL.
+func cachedThemes(ctx context.Context, runner shopify.Runner, storeID string) ([]shopify.Theme, error) {
+	dir, err := themeCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	cachePath := filepath.Join(dir, storeID+".json")
+
+	if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < themeCacheTTL {
+		if data, err := os.ReadFile(cachePath); err == nil {
+			if themes, err := shopify.ParseThemes(data); err == nil {
+				return themes, nil
+			}
+		}
+	}
+
+	themes, err := shopify.ListThemes(ctx, runner, storeID, shopify.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Caching is best effort; completion still works without it
+	if data, err := json.Marshal(themes); err == nil {
+		if err := os.MkdirAll(dir, 0755); err == nil {
+			os.WriteFile(cachePath, data, 0644)
+		}
+	}
+
+	return themes, nil
+}
@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/colinxr/shopify-theme-manager/shopify"
+)
+
+func TestThemeCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		errMsg     string
+		wantThemes map[string]string
+	}{
+		{
+			name:       "set environment",
+			args:       []string{"theme", "set", "test-alias", "preview", "123"},
+			wantThemes: map[string]string{"staging": "777", "preview": "123"},
+		},
+		{
+			name:       "overwrite environment",
+			args:       []string{"theme", "set", "test-alias", "staging", "live"},
+			wantThemes: map[string]string{"staging": "live"},
+		},
+		{
+			name:    "invalid theme ID",
+			args:    []string{"theme", "set", "test-alias", "staging", "dawn"},
+			wantErr: true,
+			errMsg:  "invalid theme ID \"dawn\"",
+		},
+		{
+			name:    "invalid environment name",
+			args:    []string{"theme", "set", "test-alias", "my env", "123"},
+			wantErr: true,
+			errMsg:  "invalid theme environment \"my env\"",
+		},
+		{
+			name:    "set on unknown store",
+			args:    []string{"theme", "set", "invalid-store", "staging", "123"},
+			wantErr: true,
+			errMsg:  "store with alias \"invalid-store\" not found",
+		},
+		{
+			name:       "unset environment",
+			args:       []string{"theme", "unset", "test-alias", "staging"},
+			wantThemes: map[string]string{},
+		},
+		{
+			name:    "unset unknown environment",
+			args:    []string{"theme", "unset", "test-alias", "preview"},
+			wantErr: true,
+			errMsg:  "store \"test-alias\" has no theme environment \"preview\"",
+		},
+		{
+			name:    "set missing theme ID",
+			args:    []string{"theme", "set", "staging"},
+			wantErr: true,
+			errMsg:  "accepts between 2 and 3 arg(s), received 1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newTestHelper(t)
+			h.mock.AddStore("test-store", "test-alias", "test-dir")
+			h.mock.SetThemeEnv("test-alias", "staging", "777")
+
+			cmd := NewThemeCommand(h.mock)
+			h.setupCommand(cmd)
+
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			themes := h.mock.GetStore("test-alias").Themes
+			if themes == nil {
+				themes = map[string]string{}
+			}
+			if !reflect.DeepEqual(themes, tt.wantThemes) {
+				t.Errorf("themes = %v, want %v", themes, tt.wantThemes)
+			}
+		})
+	}
+}
+
+func TestResolveThemeID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		themeID   string
+		env       string
+		listErr   error
+		themeList string
+		want      string
+		wantCalls int
+		errMsg    string
+	}{
+		{name: "nothing requested", want: ""},
+		{name: "explicit theme ID", themeID: "123", want: "123"},
+		{name: "environment", env: "staging", want: "777"},
+		{
+			name:      "live environment",
+			env:       "production",
+			themeList: `[{"id": 1, "role": "unpublished"}, {"id": 2, "role": "live"}]`,
+			want:      "2",
+			wantCalls: 1,
+		},
+		{
+			name:      "explicit live",
+			themeID:   "live",
+			themeList: `[{"id": 2, "role": "live"}]`,
+			want:      "2",
+			wantCalls: 1,
+		},
+		{
+			name:      "no live theme",
+			env:       "production",
+			themeList: `[{"id": 1, "role": "unpublished"}]`,
+			wantCalls: 1,
+			errMsg:    "has no live theme",
+		},
+		{
+			name:      "theme list fails",
+			env:       "production",
+			listErr:   errors.New("exit status 1"),
+			wantCalls: 1,
+			errMsg:    "shopify theme list failed",
+		},
+		{name: "unknown environment", env: "preview", errMsg: "has no theme environment \"preview\""},
+		{name: "both", themeID: "123", env: "staging", errMsg: "not both"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newTestHelper(t)
+			h.mock.AddStore("test-store", "test-alias", "test-dir")
+			h.mock.SetThemeEnv("test-alias", "staging", "777")
+			h.mock.SetThemeEnv("test-alias", "production", "live")
+			h.runner.Stdout = tt.themeList
+			h.runner.Err = tt.listErr
+
+			got, err := resolveThemeID(context.Background(), h.runner, h.mock.GetStore("test-alias"), tt.themeID, tt.env)
+
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else if got != tt.want {
+				t.Errorf("resolveThemeID() = %q, want %q", got, tt.want)
+			}
+
+			calls := h.runner.Calls()
+			if len(calls) != tt.wantCalls {
+				t.Fatalf("shopify ran %d times, want %d", len(calls), tt.wantCalls)
+			}
+			if len(calls) > 0 {
+				want := shopify.ListThemesArgs("test-store", shopify.ListOptions{})
+				if !reflect.DeepEqual(calls[0].Args, want) {
+					t.Errorf("shopify args = %v, want %v", calls[0].Args, want)
+				}
+			}
+		})
+	}
+}
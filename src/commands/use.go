@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/spf13/cobra"
+)
+
+// storeEnvVar selects the current store for one shell, overriding the
+// store saved with "stm use".
+const storeEnvVar = "STM_STORE"
+
+// storeEnv reads storeEnvVar. It is declared at package level for mocking
+// in tests.
+var storeEnv = func() string {
+	return os.Getenv(storeEnvVar)
+}
+
+// workingDir is declared at package level for mocking in tests.
+var workingDir = os.Getwd
+
+func NewUseCommand(cfg config.Manager) *cobra.Command {
+	var clearStore bool
+
+	cmd := &cobra.Command{
+		Use:   "use [store-alias]",
+		Short: "Set the current store",
+		Long: `Set the store that commands such as list, dev, push and pull use when
+no alias is given. Without an alias, print the current store.
+
+Inside a store's project directory that store is used instead. Set
+STM_STORE to override both in a single shell:
+
+  export STM_STORE=acme`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeStoreAliases(cfg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+
+			if clearStore {
+				if len(args) > 0 {
+					return fmt.Errorf("--clear does not take a store alias")
+				}
+				if err := cfg.SetCurrentStore(""); err != nil {
+					return err
+				}
+				fmt.Fprintln(out, "Current store cleared")
+				return nil
+			}
+
+			if len(args) == 0 {
+				alias, source, err := currentStore(cfg)
+				if err != nil {
+					return err
+				}
+				if alias == "" {
+					return errNoStore()
+				}
+				fmt.Fprintf(out, "%s (from %s)\n", alias, source)
+				return nil
+			}
+
+			alias := args[0]
+			if err := cfg.SetCurrentStore(alias); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Current store set to %s\n", alias)
+			if env := storeEnv(); env != "" && env != alias {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s=%s overrides the current store in this shell\n", storeEnvVar, env)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&clearStore, "clear", false, "Clear the current store")
+	return cmd
+}
+
+// currentStore returns the alias of the store commands use when none is
+// given, and where it came from. $STM_STORE wins, then the store whose
+// project directory contains the working directory, then the store saved
+// with "stm use". An empty alias means there is no current store.
+func currentStore(cfg config.Manager) (alias, source string, err error) {
+	if alias := storeEnv(); alias != "" {
+		return alias, storeEnvVar, nil
+	}
+
+	if dir, err := workingDir(); err == nil {
+		matches := config.StoresForDir(cfg.ListStores(), cfg.GetWorkspace(), dir)
+		switch len(matches) {
+		case 0:
+		case 1:
+			return matches[0].Alias, "current directory", nil
+		default:
+			aliases := make([]string, len(matches))
+			for i, store := range matches {
+				aliases[i] = store.Alias
+			}
+			return "", "", fmt.Errorf("current directory %s belongs to several stores (%s); pass a store alias", dir, strings.Join(aliases, ", "))
+		}
+	}
+
+	if alias := cfg.GetCurrentStore(); alias != "" {
+		return alias, "stm use", nil
+	}
+	return "", "", nil
+}
+
+// errNoStore explains that a command was given no alias and none could be
+// worked out.
+func errNoStore() error {
+	dir, err := workingDir()
+	if err != nil {
+		return errors.New("no store given and no current store set; pass a store alias or run: stm use <store-alias>")
+	}
+	return fmt.Errorf("no store given and no current store set, and %s is not inside a store's project directory; pass a store alias or run: stm use <store-alias>", dir)
+}
+
+// storeArg finds the store a command targets. When the first argument is a
+// configured alias it names the store and the remaining arguments are
+// returned; otherwise the current store is used and args are returned
+// unchanged. maxRest is the number of theme IDs the command accepts after
+// the alias. A first argument that isn't a theme ID is taken as a mistyped
+// alias rather than passed on for the current store.
+func storeArg(cfg config.Manager, args []string, maxRest int) (*config.Store, []string, error) {
+	if len(args) > 0 {
+		if store := cfg.GetStore(args[0]); store != nil {
+			return store, args[1:], nil
+		}
+		if len(args) > maxRest || config.ValidateThemeID(args[0]) != nil {
+			return nil, nil, fmt.Errorf("store with alias %q not found", args[0])
+		}
+	}
+
+	alias, source, err := currentStore(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if alias == "" {
+		if len(args) > 0 {
+			return nil, nil, fmt.Errorf("store with alias %q not found", args[0])
+		}
+		return nil, nil, errNoStore()
+	}
+
+	store := cfg.GetStore(alias)
+	if store == nil {
+		return nil, nil, fmt.Errorf("current store %q (from %s) not found", alias, source)
+	}
+	return store, args, nil
+}
+
+// fixedStoreArg finds the store of a command that takes n arguments after
+// an optional store alias. With n+1 arguments the first is the alias, and
+// with n the current store is used. Counting, rather than looking the first
+// argument up, keeps an environment named like a store from being taken as
+// its alias.
+func fixedStoreArg(cfg config.Manager, args []string, n int) (*config.Store, []string, error) {
+	if len(args) > n {
+		store := cfg.GetStore(args[0])
+		if store == nil {
+			return nil, nil, fmt.Errorf("store with alias %q not found", args[0])
+		}
+		return store, args[1:], nil
+	}
+	store, _, err := storeArg(cfg, nil, 0)
+	return store, args, err
+}
+
+// listStoreArg finds the store of a command that takes a list of things,
+// such as tags, after an optional store alias. A first argument that is a
+// configured alias names the store; otherwise the current store is used
+// and every argument is part of the list. what names the list's items in
+// the error for an empty list.
+func listStoreArg(cfg config.Manager, args []string, what string) (*config.Store, []string, error) {
+	if len(args) > 0 {
+		if store := cfg.GetStore(args[0]); store != nil {
+			if len(args) == 1 {
+				return nil, nil, fmt.Errorf("no %s given for store %s", what, store.Alias)
+			}
+			return store, args[1:], nil
+		}
+	}
+	store, _, err := storeArg(cfg, nil, 0)
+	return store, args, err
+}
+
+// completionStore returns the store whose values to offer when completing
+// args of a command with an optional leading store alias, or nil.
+func completionStore(cfg config.Manager, args []string) *config.Store {
+	if len(args) > 0 {
+		if store := cfg.GetStore(args[0]); store != nil {
+			return store
+		}
+	}
+	alias, _, err := currentStore(cfg)
+	if err != nil || alias == "" {
+		return nil
+	}
+	return cfg.GetStore(alias)
+}
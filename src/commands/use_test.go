@@ -0,0 +1,298 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUseCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		current     string
+		env         string
+		wantErr     bool
+		errMsg      string
+		wantCurrent string
+		wantOutput  string
+	}{
+		{
+			name:        "set current store",
+			args:        []string{"use", "beta"},
+			wantCurrent: "beta",
+			wantOutput:  "Current store set to beta\n",
+		},
+		{
+			name:        "show current store",
+			args:        []string{"use"},
+			current:     "alpha",
+			wantCurrent: "alpha",
+			wantOutput:  "alpha (from stm use)\n",
+		},
+		{
+			name:        "show store from environment",
+			args:        []string{"use"},
+			current:     "alpha",
+			env:         "beta",
+			wantCurrent: "alpha",
+			wantOutput:  "beta (from STM_STORE)\n",
+		},
+		{
+			name:        "environment overrides new store",
+			args:        []string{"use", "alpha"},
+			env:         "beta",
+			wantCurrent: "alpha",
+			wantOutput:  "Current store set to alpha\nWarning: STM_STORE=beta overrides the current store in this shell\n",
+		},
+		{
+			name:       "clear current store",
+			args:       []string{"use", "--clear"},
+			current:    "alpha",
+			wantOutput: "Current store cleared\n",
+		},
+		{
+			name:    "no current store",
+			args:    []string{"use"},
+			wantErr: true,
+			errMsg:  "no current store set",
+		},
+		{
+			name:        "unknown store",
+			args:        []string{"use", "invalid-store"},
+			current:     "alpha",
+			wantErr:     true,
+			errMsg:      "store with alias \"invalid-store\" not found",
+			wantCurrent: "alpha",
+		},
+		{
+			name:    "clear with alias",
+			args:    []string{"use", "alpha", "--clear"},
+			wantErr: true,
+			errMsg:  "--clear does not take a store alias",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer MockStoreEnv(tt.env)()
+			h := newTestHelper(t)
+			h.mock.AddStore("alpha-store", "alpha", "alpha-dir")
+			h.mock.AddStore("beta-store", "beta", "beta-dir")
+			h.mock.SetCurrentStore(tt.current)
+
+			h.setupCommand(NewUseCommand(h.mock))
+			h.cmd.SetArgs(tt.args)
+			err := h.cmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				} else if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else if h.output.String() != tt.wantOutput {
+				t.Errorf("output = %q, want %q", h.output.String(), tt.wantOutput)
+			}
+
+			if got := h.mock.GetCurrentStore(); got != tt.wantCurrent {
+				t.Errorf("current store = %q, want %q", got, tt.wantCurrent)
+			}
+		})
+	}
+}
+
+func TestStoreArg(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		maxRest   int
+		current   string
+		env       string
+		dir       string
+		wantAlias string
+		wantRest  []string
+		errMsg    string
+	}{
+		{name: "alias argument", args: []string{"beta", "123"}, maxRest: 1, current: "alpha", wantAlias: "beta", wantRest: []string{"123"}},
+		{name: "current store", args: []string{"123"}, maxRest: 1, current: "alpha", wantAlias: "alpha", wantRest: []string{"123"}},
+		{name: "current store with live theme", args: []string{"live"}, maxRest: 1, current: "alpha", wantAlias: "alpha", wantRest: []string{"live"}},
+		{name: "mistyped alias with current store", args: []string{"alphaa"}, maxRest: 1, current: "alpha", errMsg: "store with alias \"alphaa\" not found"},
+		{name: "mistyped alias in store directory", args: []string{"betaa"}, maxRest: 1, dir: "/workspace/beta-dir", errMsg: "store with alias \"betaa\" not found"},
+		{name: "environment wins", args: nil, current: "alpha", env: "beta", wantAlias: "beta", wantRest: nil},
+		{name: "no store", args: nil, errMsg: "no current store set"},
+		{name: "unknown alias without current store", args: []string{"gamma"}, errMsg: "store with alias \"gamma\" not found"},
+		{name: "unknown alias with current store", args: []string{"gamma"}, current: "alpha", errMsg: "store with alias \"gamma\" not found"},
+		{name: "current store removed", env: "gamma", errMsg: "current store \"gamma\" (from STM_STORE) not found"},
+		{name: "working directory", dir: "/workspace/beta-dir/sections", current: "alpha", wantAlias: "beta"},
+		{name: "environment beats working directory", dir: "/workspace/beta-dir", env: "alpha", wantAlias: "alpha"},
+		{name: "alias beats working directory", args: []string{"alpha"}, dir: "/workspace/beta-dir", wantAlias: "alpha"},
+		{name: "working directory outside stores", dir: "/workspace", errMsg: "/workspace is not inside a store's project directory"},
+		{name: "working directory with several stores", dir: "/workspace/shared/snippets", current: "alpha", errMsg: "belongs to several stores (shared-a, shared-b)"},
+		{name: "unknown alias in shared directory", args: []string{"gamma", "1"}, maxRest: 1, dir: "/workspace/shared", errMsg: "store with alias \"gamma\" not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := tt.dir
+			if dir == "" {
+				dir = "/elsewhere"
+			}
+			defer MockStoreEnv(tt.env)()
+			defer MockWorkingDir(dir)()
+			h := newTestHelper(t)
+			h.mock.SetWorkspace("/workspace")
+			h.mock.AddStore("alpha-store", "alpha", "alpha-dir")
+			h.mock.AddStore("beta-store", "beta", "beta-dir")
+			h.mock.AddStore("shared-a-store", "shared-a", "shared")
+			h.mock.AddStore("shared-b-store", "shared-b", "/workspace/shared")
+			h.mock.SetCurrentStore(tt.current)
+
+			store, rest, err := storeArg(h.mock, tt.args, tt.maxRest)
+
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if store.Alias != tt.wantAlias {
+				t.Errorf("store = %s, want %s", store.Alias, tt.wantAlias)
+			}
+			if len(rest) != len(tt.wantRest) || (len(rest) > 0 && !reflect.DeepEqual(rest, tt.wantRest)) {
+				t.Errorf("rest = %v, want %v", rest, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestCurrentStoreCommands(t *testing.T) {
+	defer MockStoreEnv("")()
+	defer MockWorkingDir("/elsewhere")()
+	h := newTestHelper(t)
+	h.mock.SetWorkspace("/workspace")
+	h.mock.AddStore("test-store", "test-alias", "test-dir")
+	h.mock.SetCurrentStore("test-alias")
+
+	h.setupCommand(NewDevCommand(h.mock, h.runner))
+	h.setupCommand(NewListCommand(h.mock, h.runner))
+	h.setupCommand(NewPullCommand(h.mock, h.runner))
+
+	for _, tt := range []struct {
+		args []string
+		want []string
+	}{
+		{args: []string{"dev"}, want: []string{"theme", "dev", "--store", "test-store"}},
+		{args: []string{"dev", "123"}, want: []string{"theme", "dev", "--store", "test-store", "--theme", "123"}},
+		{args: []string{"list"}, want: []string{"theme", "list", "--store", "test-store", "--json"}},
+		{args: []string{"pull", "--nodelete"}, want: []string{"theme", "pull", "--store", "test-store", "--nodelete"}},
+	} {
+		h.cmd.SetArgs(tt.args)
+		if err := h.cmd.Execute(); err != nil {
+			t.Fatalf("%v: unexpected error: %v", tt.args, err)
+		}
+		if got := h.runner.LastCall().Args; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v ran shopify %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestCurrentStoreCommands_NoCurrentStore(t *testing.T) {
+	defer MockStoreEnv("")()
+	defer MockWorkingDir("/elsewhere")()
+	h := newTestHelper(t)
+	h.mock.SetWorkspace("/workspace")
+	h.mock.AddStore("test-store", "test-alias", "test-dir")
+
+	h.setupCommand(NewCdCommand(h.mock))
+	h.setupCommand(NewDevCommand(h.mock, h.runner))
+	h.setupCommand(NewListCommand(h.mock, h.runner))
+	h.setupCommand(NewPushCommand(h.mock, h.runner))
+	h.setupCommand(NewPullCommand(h.mock, h.runner))
+	h.setupCommand(NewThemeCommand(h.mock))
+	h.setupCommand(NewTagCommand(h.mock))
+	h.setupCommand(NewIgnoreCommand(h.mock))
+	h.setupCommand(NewProtectCommand(h.mock))
+	h.setupCommand(NewEditCommand(h.mock))
+
+	for _, args := range [][]string{
+		{"cd"}, {"dev"}, {"list"}, {"push"}, {"pull"},
+		{"theme", "set", "staging", "123"}, {"theme", "unset", "staging"},
+		{"tag", "add", "tier:plus"}, {"ignore", "add", "locales/*"}, {"ignore", "list"},
+		{"protect"}, {"edit"},
+	} {
+		h.cmd.SetArgs(args)
+		err := h.cmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), "no current store set") {
+			t.Errorf("%v: error = %v, want error containing %q", args, err, "no current store set")
+		}
+	}
+	if calls := h.runner.Calls(); len(calls) != 0 {
+		t.Errorf("shopify ran %v without a store", calls[0].Args)
+	}
+}
+
+func TestCurrentStoreConfigCommands(t *testing.T) {
+	defer MockStoreEnv("")()
+	defer MockWorkingDir("/elsewhere")()
+	h := newTestHelper(t)
+	h.mock.SetWorkspace("/workspace")
+	h.mock.AddStore("alpha-store", "alpha", "alpha-dir")
+	h.mock.AddStore("beta-store", "beta", "beta-dir")
+	h.mock.SetCurrentStore("alpha")
+
+	h.setupCommand(NewThemeCommand(h.mock))
+	h.setupCommand(NewTagCommand(h.mock))
+	h.setupCommand(NewIgnoreCommand(h.mock))
+	h.setupCommand(NewProtectCommand(h.mock))
+	h.setupCommand(NewUnprotectCommand(h.mock))
+
+	for _, tt := range []struct {
+		args   []string
+		output string
+		errMsg string
+	}{
+		{args: []string{"theme", "set", "staging", "123"}, output: "Theme environment staging of store alpha set to 123\n"},
+		{args: []string{"theme", "set", "beta", "staging", "456"}, output: "Theme environment staging of store beta set to 456\n"},
+		{args: []string{"theme", "set", "betaa", "staging", "456"}, errMsg: "store with alias \"betaa\" not found"},
+		{args: []string{"theme", "unset", "staging"}, output: "Theme environment staging removed from store alpha\n"},
+		{args: []string{"tag", "add", "tier:plus", "base:dawn"}, output: "Store alpha tagged tier:plus, base:dawn\n"},
+		{args: []string{"tag", "add", "beta", "tier:plus"}, output: "Store beta tagged tier:plus\n"},
+		{args: []string{"tag", "remove", "base:dawn"}, output: "Removed base:dawn from store alpha\n"},
+		{args: []string{"tag", "add", "beta"}, errMsg: "no tags given for store beta"},
+		{args: []string{"ignore", "add", "locales/*"}, output: "Store alpha now ignores locales/*\n"},
+		{args: []string{"ignore", "list"}, output: "locales/*\n"},
+		{args: []string{"ignore", "remove", "locales/*"}, output: "Store alpha no longer ignores locales/*\n"},
+		{args: []string{"protect"}, output: "Store alpha is now protected\n"},
+		{args: []string{"unprotect"}, output: "Store alpha is no longer protected\n"},
+		{args: []string{"protect", "gamma"}, errMsg: "store with alias \"gamma\" not found"},
+	} {
+		h.output.Reset()
+		h.cmd.SetArgs(tt.args)
+		err := h.cmd.Execute()
+		if tt.errMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("%v: error = %v, want error containing %q", tt.args, err, tt.errMsg)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", tt.args, err)
+		}
+		if got := h.output.String(); got != tt.output {
+			t.Errorf("%v: output = %q, want %q", tt.args, got, tt.output)
+		}
+	}
+
+	if got := h.mock.GetStore("alpha").Tags; !reflect.DeepEqual(got, []string{"tier:plus"}) {
+		t.Errorf("alpha tags = %v, want [tier:plus]", got)
+	}
+	if got := h.mock.GetStore("beta").Themes["staging"]; got != "456" {
+		t.Errorf("beta staging theme = %q, want 456", got)
+	}
+}
@@ -2,15 +2,20 @@ package commands
 
 import (
 	"fmt"
-	"github.com/spf13/cobra"
+	"path/filepath"
+
 	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/spf13/cobra"
 )
 
 func NewSetWorkspaceCommand(cfg config.Manager) *cobra.Command {
 	return &cobra.Command{
 		Use:   "set-workspace [directory]",
 		Short: "Set the workspace directory for all projects",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Set the directory that relative project directories are resolved
+against. The directory must exist; when running interactively stm offers
+to create it.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Use current directory if no argument provided
 			workspacePath := "."
@@ -18,12 +23,20 @@ func NewSetWorkspaceCommand(cfg config.Manager) *cobra.Command {
 				workspacePath = args[0]
 			}
 
+			absPath, err := filepath.Abs(workspacePath)
+			if err != nil {
+				return fmt.Errorf("invalid workspace path: %w", err)
+			}
+			if err := ensureDir(cmd, absPath, stdinIsTerminal()); err != nil {
+				return fmt.Errorf("invalid workspace path: %w", err)
+			}
+
 			if err := cfg.SetWorkspace(workspacePath); err != nil {
 				return fmt.Errorf("failed to set workspace: %w", err)
 			}
 
-			fmt.Printf("Workspace set to: %s\n", cfg.GetWorkspace())
+			fmt.Fprintf(cmd.OutOrStdout(), "Workspace set to: %s\n", cfg.GetWorkspace())
 			return nil
 		},
 	}
-} 
\ No newline at end of file
+}
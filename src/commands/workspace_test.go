@@ -1,8 +1,12 @@
 package commands
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/manifoldco/promptui"
 )
 
 func TestSetWorkspaceCommand(t *testing.T) {
@@ -15,10 +19,16 @@ func TestSetWorkspaceCommand(t *testing.T) {
 	}{
 		{
 			name:     "set workspace with explicit path",
-			args:     []string{"set-workspace", "/test/workspace"},
-			wantPath: "/test/workspace",
+			args:     []string{"set-workspace", "<tmp>"},
+			wantPath: "<tmp>",
 			wantErr:  false,
 		},
+		{
+			name:    "missing directory",
+			args:    []string{"set-workspace", "<tmp>/missing"},
+			wantErr: true,
+			errMsg:  "does not exist; create it first",
+		},
 		{
 			name:     "set workspace with current directory",
 			args:     []string{"set-workspace"},
@@ -36,11 +46,17 @@ func TestSetWorkspaceCommand(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			h := newTestHelper(t)
+			defer MockStdinIsTerminal(false)()
+			tmp := t.TempDir()
+			args := make([]string, len(tt.args))
+			for i, arg := range tt.args {
+				args[i] = strings.Replace(arg, "<tmp>", tmp, 1)
+			}
 
 			cmd := NewSetWorkspaceCommand(h.mock)
 			h.setupCommand(cmd)
 
-			h.cmd.SetArgs(tt.args)
+			h.cmd.SetArgs(args)
 			err := h.cmd.Execute()
 
 			if tt.wantErr {
@@ -58,8 +74,8 @@ func TestSetWorkspaceCommand(t *testing.T) {
 			}
 
 			// Verify the workspace was set correctly
-			if got := h.mock.GetWorkspace(); got != tt.wantPath {
-				t.Errorf("workspace = %v, want %v", got, tt.wantPath)
+			if got, want := h.mock.GetWorkspace(), strings.Replace(tt.wantPath, "<tmp>", tmp, 1); got != want {
+				t.Errorf("workspace = %v, want %v", got, want)
 			}
 		})
 	}
@@ -83,3 +99,24 @@ func TestSetWorkspaceCommand_InvalidPath(t *testing.T) {
 		t.Errorf("expected error about invalid workspace path, got: %v", err)
 	}
 }
+
+func TestSetWorkspaceCommand_CreateDir(t *testing.T) {
+	h := newTestHelper(t)
+	defer MockStdinIsTerminal(true)()
+	defer MockPrompt(func(p promptui.Prompt) (string, error) {
+		return "y", nil
+	})()
+	workspace := filepath.Join(t.TempDir(), "projects")
+
+	h.setupCommand(NewSetWorkspaceCommand(h.mock))
+	h.cmd.SetArgs([]string{"set-workspace", workspace})
+	if err := h.cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info, err := os.Stat(workspace); err != nil || !info.IsDir() {
+		t.Errorf("workspace was not created: %v", err)
+	}
+	if got := h.mock.GetWorkspace(); got != workspace {
+		t.Errorf("workspace = %v, want %v", got, workspace)
+	}
+}
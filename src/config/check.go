@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CheckConfigFile reads and decodes the config file at path, as loading it
+// would, and returns the schema version it was saved with.
+func CheckConfigFile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	_, version, _, err := decodeConfig(data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	return version, nil
+}
+
+// CheckWritable checks that the config file at path can be saved. Saves
+// write a temporary file next to it and rename it into place, so its
+// directory must be writable.
+func CheckWritable(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	name := tmp.Name()
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
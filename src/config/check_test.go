@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCheckConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name        string
+		data        string
+		wantVersion int
+		errMsg      string
+	}{
+		{name: "current", data: `{"version": 6, "stores": []}`, wantVersion: 6},
+		{name: "older version", data: `{"version": 2, "stores": []}`, wantVersion: 2},
+		{name: "invalid JSON", data: `{"version": `, errMsg: "failed to read config"},
+		{name: "newer version", data: `{"version": 999}`, errMsg: "newer than this stm supports"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "-")+".json")
+			if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			version, err := CheckConfigFile(path)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("CheckConfigFile() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckConfigFile() error = %v", err)
+			}
+			if version != tt.wantVersion {
+				t.Errorf("CheckConfigFile() version = %d, want %d", version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := CheckWritable(path); err != nil {
+		t.Errorf("CheckWritable() error = %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("CheckWritable() left %d files behind", len(entries))
+	}
+
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("directory permissions are not enforced")
+	}
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(dir, 0755)
+	if err := CheckWritable(path); err == nil {
+		t.Error("CheckWritable() in a read-only directory succeeded")
+	}
+}
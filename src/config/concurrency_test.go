@@ -0,0 +1,166 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+const concurrentWriters = 20
+
+func TestAddStore_ConcurrentManagers(t *testing.T) {
+	m := newTestManager(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrentWriters)
+	for i := 0; i < concurrentWriters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Each manager stands in for a separate stm invocation
+			other, err := NewManager()
+			if err != nil {
+				errs <- err
+				return
+			}
+			errs <- other.AddStore(fmt.Sprintf("store-%d", i), fmt.Sprintf("alias-%d", i), "dir")
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("AddStore() error = %v", err)
+		}
+	}
+
+	if got := len(reload(t, m).ListStores()); got != concurrentWriters {
+		t.Errorf("saved %d stores, want %d", got, concurrentWriters)
+	}
+}
+
+func TestAddStore_SharedManager(t *testing.T) {
+	m := newTestManager(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrentWriters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := m.AddStore(fmt.Sprintf("store-%d", i), fmt.Sprintf("alias-%d", i), "dir"); err != nil {
+				t.Errorf("AddStore() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := len(reload(t, m).ListStores()); got != concurrentWriters {
+		t.Errorf("saved %d stores, want %d", got, concurrentWriters)
+	}
+}
+
+func TestAddStore_ConcurrentProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("spawns subprocesses")
+	}
+	m := newTestManager(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrentWriters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcessAddStore$")
+			cmd.Env = append(os.Environ(),
+				"STM_TEST_HELPER_PROCESS=1",
+				fmt.Sprintf("STM_TEST_STORE_INDEX=%d", i),
+			)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("helper process %d failed: %v\n%s", i, err, out)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := len(reload(t, m).ListStores()); got != concurrentWriters {
+		t.Errorf("saved %d stores, want %d", got, concurrentWriters)
+	}
+}
+
+// TestHelperProcessAddStore is run as a subprocess by
+// TestAddStore_ConcurrentProcesses; HOME is inherited from the parent test.
+func TestHelperProcessAddStore(t *testing.T) {
+	if os.Getenv("STM_TEST_HELPER_PROCESS") != "1" {
+		t.Skip("helper process")
+	}
+	index := os.Getenv("STM_TEST_STORE_INDEX")
+
+	m, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddStore("store-"+index, "alias-"+index, "dir"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSaveConfig_KeepsBackup(t *testing.T) {
+	m := newTestManager(t)
+	if err := m.AddStore("store-a", "a", "dir-a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddStore("store-b", "b", "dir-b"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(m.configPath + ".bak")
+	if err != nil {
+		t.Fatalf("reading backup: %v", err)
+	}
+	var backup Config
+	if err := json.Unmarshal(data, &backup); err != nil {
+		t.Fatalf("backup is not valid JSON: %v", err)
+	}
+	if len(backup.Stores) != 1 || backup.Stores[0].Alias != "a" {
+		t.Errorf("backup stores = %+v, want the previous version with only a", backup.Stores)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(m.configDir, "*.tmp"))
+	if len(matches) != 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
+
+func TestUpdate_CorruptConfig(t *testing.T) {
+	m := newTestManager(t)
+	if err := m.AddStore("store-a", "a", "dir-a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(m.configPath, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddStore("store-b", "b", "dir-b"); err == nil {
+		t.Fatal("AddStore() on corrupt config succeeded, want error")
+	}
+
+	// Saving over the corrupt file must not copy it into the backup
+	if err := m.saveConfig(); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := os.ReadFile(m.configPath + ".bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(backup) {
+		t.Errorf("backup = %q, want last good JSON", backup)
+	}
+	if reload(t, m).GetStore("a") == nil {
+		t.Error("store a missing after restoring in-memory config")
+	}
+}
@@ -2,45 +2,157 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 )
 
 type Store struct {
 	StoreID    string `json:"storeId"`
 	Alias      string `json:"alias"`
 	ProjectDir string `json:"projectDir"`
+	// Themes maps environment names such as "staging" to a theme ID, or
+	// to LiveTheme for the published theme.
+	Themes map[string]string `json:"themes,omitempty"`
+	// Protected forbids any write to the store's live theme, even with
+	// --allow-live.
+	Protected bool `json:"protected,omitempty"`
+	// Ignore lists glob patterns that push and pull always pass to the
+	// Shopify CLI's --ignore flag.
+	Ignore []string `json:"ignore,omitempty"`
+	// Tags group stores for commands that run across many of them.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// clone returns a copy of the store that shares no maps or slices with s.
+func (s Store) clone() Store {
+	if s.Themes != nil {
+		themes := make(map[string]string, len(s.Themes))
+		for env, themeID := range s.Themes {
+			themes[env] = themeID
+		}
+		s.Themes = themes
+	}
+	if s.Ignore != nil {
+		s.Ignore = append([]string(nil), s.Ignore...)
+	}
+	if s.Tags != nil {
+		s.Tags = append([]string(nil), s.Tags...)
+	}
+	return s
+}
+
+// HasTag reports whether the store is tagged with tag.
+func (s *Store) HasTag(tag string) bool {
+	for _, t := range s.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectPath returns the store's project directory resolved against the
+// given workspace. Absolute project directories are returned unchanged.
+func (s *Store) ProjectPath(workspace string) string {
+	if filepath.IsAbs(s.ProjectDir) || workspace == "" {
+		return s.ProjectDir
+	}
+	return filepath.Join(workspace, s.ProjectDir)
 }
 
 type Config struct {
+	Version   int     `json:"version"`
 	Stores    []Store `json:"stores"`
 	Workspace string  `json:"workspace"`
+	// CurrentStore is the alias of the store selected with "stm use".
+	CurrentStore string `json:"currentStore,omitempty"`
 }
 
 type Manager interface {
 	AddStore(storeID, alias, projectDir string) error
 	GetStore(alias string) *Store
+	ListStores() []Store
+	UpdateStore(alias string, store Store) error
+	RemoveStore(alias string) error
+	SetThemeEnv(alias, env, themeID string) error
+	UnsetThemeEnv(alias, env string) error
+	AddIgnorePatterns(alias string, patterns ...string) error
+	RemoveIgnorePatterns(alias string, patterns ...string) error
+	AddTags(alias string, tags ...string) error
+	RemoveTags(alias string, tags ...string) error
 	SetWorkspace(path string) error
 	GetWorkspace() string
+	SetCurrentStore(alias string) error
+	GetCurrentStore() string
+	ConfigPath() string
+	MigrationPlan() (*MigrationPlan, error)
+	Migrate() error
 }
 
 type ConfigManager struct {
 	configDir  string
 	configPath string
 	config     *Config
+
+	// mu serializes updates made through this manager; the file lock
+	// taken in withLock serializes them across processes.
+	mu sync.Mutex
 }
 
-func NewManager() (Manager, error) {
+// Environment variables that influence where the config file lives
+const (
+	ConfigEnvVar        = "STM_CONFIG"
+	xdgConfigHomeEnvVar = "XDG_CONFIG_HOME"
+)
+
+const (
+	configDirName  = "shopify-theme-manager"
+	configFileName = "config.json"
+)
+
+// ResolvePath returns the config file location. An explicit path (from the
+// --config flag) wins, followed by $STM_CONFIG, then
+// $XDG_CONFIG_HOME/shopify-theme-manager/config.json and finally
+// ~/.config/shopify-theme-manager/config.json.
+func ResolvePath(explicit string) (string, error) {
+	if explicit != "" {
+		return filepath.Abs(explicit)
+	}
+
+	if path := os.Getenv(ConfigEnvVar); path != "" {
+		return filepath.Abs(path)
+	}
+
+	// The XDG spec says relative values must be ignored
+	if xdgHome := os.Getenv(xdgConfigHomeEnvVar); filepath.IsAbs(xdgHome) {
+		return filepath.Join(xdgHome, configDirName, configFileName), nil
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
+	return filepath.Join(homeDir, ".config", configDirName, configFileName), nil
+}
+
+// NewManager loads the config file from its default location, creating it
+// if needed. See ResolvePath.
+func NewManager() (Manager, error) {
+	return NewManagerAt("")
+}
 
-	configDir := filepath.Join(homeDir, ".config", "shopify-theme-manager")
-	configPath := filepath.Join(configDir, "config.json")
+// NewManagerAt is like NewManager but honors an explicit config file path,
+// as passed with --config.
+func NewManagerAt(path string) (Manager, error) {
+	configPath, err := ResolvePath(path)
+	if err != nil {
+		return nil, err
+	}
 
 	m := &ConfigManager{
-		configDir:  configDir,
+		configDir:  filepath.Dir(configPath),
 		configPath: configPath,
 	}
 
@@ -48,27 +160,79 @@ func NewManager() (Manager, error) {
 		return nil, err
 	}
 
-	if err := m.loadConfig(); err != nil {
-		return nil, err
+	if err := m.withLock(m.loadConfig); err != nil {
+		m.config = &Config{Version: CurrentVersion, Stores: []Store{}}
+		return nil, &LoadError{Manager: m, Err: err}
 	}
 
 	return m, nil
 }
 
+// LoadError is returned by NewManagerAt when the config file exists but
+// can't be read. Manager still reports the file's path, for commands such
+// as stm doctor that diagnose the file rather than use it. It has no
+// stores, and every change fails with Err.
+type LoadError struct {
+	Manager Manager
+	Err     error
+}
+
+func (e *LoadError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
 func (m *ConfigManager) ensureConfigExists() error {
 	if err := os.MkdirAll(m.configDir, 0755); err != nil {
 		return err
 	}
 
-	if _, err := os.Stat(m.configPath); os.IsNotExist(err) {
-		config := Config{
-			Stores: []Store{},
+	return m.withLock(func() error {
+		if _, err := os.Stat(m.configPath); os.IsNotExist(err) {
+			config := Config{
+				Version: CurrentVersion,
+				Stores:  []Store{},
+			}
+			m.config = &config
+			return m.saveConfig()
 		}
-		m.config = &config
-		return m.saveConfig()
+		return nil
+	})
+}
+
+// withLock runs fn while holding the advisory lock on the config file.
+func (m *ConfigManager) withLock(fn func() error) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lock, err := lockFile(m.configPath + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock config: %w", err)
 	}
+	defer func() {
+		if unlockErr := lock.unlock(); err == nil {
+			err = unlockErr
+		}
+	}()
 
-	return nil
+	return fn()
+}
+
+// update reloads the config under the lock, applies fn and saves the
+// result, so concurrent stm processes never overwrite each other's changes.
+func (m *ConfigManager) update(fn func(config *Config) error) error {
+	return m.withLock(func() error {
+		if err := m.loadConfig(); err != nil {
+			return err
+		}
+		if err := fn(m.config); err != nil {
+			return err
+		}
+		return m.saveConfig()
+	})
 }
 
 func (m *ConfigManager) loadConfig() error {
@@ -77,12 +241,14 @@ func (m *ConfigManager) loadConfig() error {
 		return err
 	}
 
-	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
-		return err
+	// Older files are migrated in memory; the upgraded schema is written
+	// on the next save.
+	config, _, _, err := decodeConfig(data)
+	if err != nil {
+		return fmt.Errorf("failed to read config %s: %w", m.configPath, err)
 	}
 
-	m.config = &config
+	m.config = config
 	return nil
 }
 
@@ -92,37 +258,200 @@ func (m *ConfigManager) saveConfig() error {
 		return err
 	}
 
-	return os.WriteFile(m.configPath, data, 0644)
+	if err := m.backupConfig(); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(m.configPath, data, 0644)
 }
 
+// backupConfig copies the current config file to config.json.bak. A file
+// that no longer parses is not copied, so the backup is always the last
+// good version. Files from an older schema version are also kept as
+// config.json.v<version>.bak before being overwritten by the migrated form.
+func (m *ConfigManager) backupConfig() error {
+	data, err := os.ReadFile(m.configPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+	if version, err := documentVersion(doc); err == nil && version < CurrentVersion {
+		versioned := fmt.Sprintf("%s.v%d.bak", m.configPath, version)
+		if err := writeFileAtomic(versioned, data, 0644); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(m.configPath+".bak", data, 0644)
+}
+
+// MigrationPlan reports the migrations needed to bring the config file on
+// disk up to CurrentVersion, without writing anything.
+func (m *ConfigManager) MigrationPlan() (*MigrationPlan, error) {
+	data, err := os.ReadFile(m.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config, fromVersion, steps, err := decodeConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &MigrationPlan{
+		FromVersion: fromVersion,
+		ToVersion:   CurrentVersion,
+		Steps:       steps,
+		Result:      result,
+	}, nil
+}
+
+// Migrate rewrites the config file in the current schema version. The
+// previous file is backed up first.
+func (m *ConfigManager) Migrate() error {
+	return m.update(func(config *Config) error {
+		return nil
+	})
+}
+
+// AddStore validates and appends a new store. The store ID is normalized
+// and must not clash with an existing alias or store ID. The project
+// directory must exist; see CheckDir.
 func (m *ConfigManager) AddStore(storeID, alias, projectDir string) error {
 	store := Store{
 		StoreID:    storeID,
 		Alias:      alias,
 		ProjectDir: projectDir,
 	}
-	m.config.Stores = append(m.config.Stores, store)
-	return m.saveConfig()
+	return m.update(func(config *Config) error {
+		if err := m.validateStore(&store, -1); err != nil {
+			return err
+		}
+		if err := CheckDir(store.ProjectPath(config.Workspace)); err != nil {
+			return err
+		}
+		config.Stores = append(config.Stores, store)
+		return nil
+	})
 }
 
 func (m *ConfigManager) GetStore(alias string) *Store {
 	for _, store := range m.config.Stores {
 		if store.Alias == alias {
+			store = store.clone()
 			return &store
 		}
 	}
 	return nil
 }
 
+// ListStores returns a copy of every configured store in config order.
+func (m *ConfigManager) ListStores() []Store {
+	stores := make([]Store, len(m.config.Stores))
+	for i, store := range m.config.Stores {
+		stores[i] = store.clone()
+	}
+	return stores
+}
+
+// UpdateStore replaces the store identified by alias. The replacement may
+// carry a different alias, which renames the store. A changed project
+// directory must exist, as in AddStore.
+func (m *ConfigManager) UpdateStore(alias string, store Store) error {
+	return m.update(func(config *Config) error {
+		index := m.storeIndex(alias)
+		if index < 0 {
+			return fmt.Errorf("store with alias %q not found", alias)
+		}
+		if err := m.validateStore(&store, index); err != nil {
+			return err
+		}
+		if store.ProjectDir != config.Stores[index].ProjectDir {
+			if err := CheckDir(store.ProjectPath(config.Workspace)); err != nil {
+				return err
+			}
+		}
+		config.Stores[index] = store
+		if config.CurrentStore == alias {
+			config.CurrentStore = store.Alias
+		}
+		return nil
+	})
+}
+
+func (m *ConfigManager) RemoveStore(alias string) error {
+	return m.update(func(config *Config) error {
+		index := m.storeIndex(alias)
+		if index < 0 {
+			return fmt.Errorf("store with alias %q not found", alias)
+		}
+		config.Stores = append(config.Stores[:index], config.Stores[index+1:]...)
+		if config.CurrentStore == alias {
+			config.CurrentStore = ""
+		}
+		return nil
+	})
+}
+
+func (m *ConfigManager) storeIndex(alias string) int {
+	for i, store := range m.config.Stores {
+		if store.Alias == alias {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetWorkspace sets the directory relative project directories are
+// resolved against. The directory must exist.
 func (m *ConfigManager) SetWorkspace(path string) error {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return err
 	}
-	m.config.Workspace = absPath
-	return m.saveConfig()
+	if err := CheckDir(absPath); err != nil {
+		return err
+	}
+	return m.update(func(config *Config) error {
+		config.Workspace = absPath
+		return nil
+	})
+}
+
+// ConfigPath returns the resolved location of the config file.
+func (m *ConfigManager) ConfigPath() string {
+	return m.configPath
 }
 
 func (m *ConfigManager) GetWorkspace() string {
 	return m.config.Workspace
-} 
\ No newline at end of file
+}
+
+// SetCurrentStore selects the store that commands use when no alias is
+// given. An empty alias clears the selection.
+func (m *ConfigManager) SetCurrentStore(alias string) error {
+	return m.update(func(config *Config) error {
+		if alias != "" && m.storeIndex(alias) < 0 {
+			return fmt.Errorf("store with alias %q not found", alias)
+		}
+		config.CurrentStore = alias
+		return nil
+	})
+}
+
+// GetCurrentStore returns the alias selected with SetCurrentStore, or an
+// empty string.
+func (m *ConfigManager) GetCurrentStore() string {
+	return m.config.CurrentStore
+}
@@ -0,0 +1,241 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testProjectDirs are created in the workspace of newTestManager, so stores
+// can be added with them.
+var testProjectDirs = []string{"dir", "dir-a", "dir-b", "dir-c"}
+
+// newTestManager returns a ConfigManager backed by a temporary home
+// directory, with a workspace holding testProjectDirs.
+func newTestManager(t *testing.T) *ConfigManager {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(ConfigEnvVar, "")
+	t.Setenv(xdgConfigHomeEnvVar, "")
+
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	workspace := filepath.Join(home, "workspace")
+	for _, dir := range testProjectDirs {
+		if err := os.MkdirAll(filepath.Join(workspace, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.SetWorkspace(workspace); err != nil {
+		t.Fatalf("SetWorkspace() error = %v", err)
+	}
+	return m.(*ConfigManager)
+}
+
+// reload returns a fresh manager reading the same config file as m.
+func reload(t *testing.T, m *ConfigManager) *ConfigManager {
+	t.Helper()
+	fresh := &ConfigManager{configDir: m.configDir, configPath: m.configPath}
+	if err := fresh.loadConfig(); err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	return fresh
+}
+
+func TestUpdateStore(t *testing.T) {
+	m := newTestManager(t)
+	m.AddStore("store-a", "a", "dir-a")
+	m.AddStore("store-b", "b", "dir-b")
+
+	if err := m.UpdateStore("a", Store{StoreID: "store-c", Alias: "c", ProjectDir: "dir-c"}); err != nil {
+		t.Fatalf("UpdateStore() error = %v", err)
+	}
+
+	saved := reload(t, m)
+	if saved.GetStore("a") != nil {
+		t.Error("old alias still present after rename")
+	}
+	if store := saved.GetStore("c"); store == nil || store.StoreID != "store-c.myshopify.com" || store.ProjectDir != "dir-c" {
+		t.Errorf("GetStore(c) = %+v, want updated store", store)
+	}
+
+	err := m.UpdateStore("c", Store{StoreID: "store-c", Alias: "b", ProjectDir: "dir-c"})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("UpdateStore() to existing alias error = %v, want already exists", err)
+	}
+
+	err = m.UpdateStore("missing", Store{Alias: "missing"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("UpdateStore() on missing alias error = %v, want not found", err)
+	}
+}
+
+func TestRemoveStore(t *testing.T) {
+	m := newTestManager(t)
+	m.AddStore("store-a", "a", "dir-a")
+	m.AddStore("store-b", "b", "dir-b")
+
+	if err := m.RemoveStore("a"); err != nil {
+		t.Fatalf("RemoveStore() error = %v", err)
+	}
+
+	saved := reload(t, m)
+	if saved.GetStore("a") != nil {
+		t.Error("store a still present after removal")
+	}
+	if saved.GetStore("b") == nil {
+		t.Error("store b was removed")
+	}
+
+	if err := m.RemoveStore("a"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("RemoveStore() on missing alias error = %v, want not found", err)
+	}
+}
+
+func TestCurrentStore(t *testing.T) {
+	m := newTestManager(t)
+	m.AddStore("store-a", "a", "dir-a")
+	m.AddStore("store-b", "b", "dir-b")
+
+	if err := m.SetCurrentStore("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("SetCurrentStore() on missing alias error = %v, want not found", err)
+	}
+	if err := m.SetCurrentStore("a"); err != nil {
+		t.Fatalf("SetCurrentStore() error = %v", err)
+	}
+	if got := reload(t, m).GetCurrentStore(); got != "a" {
+		t.Errorf("saved current store = %q, want a", got)
+	}
+
+	// Renaming the current store follows it
+	if err := m.UpdateStore("a", Store{StoreID: "store-a", Alias: "c", ProjectDir: "dir-a"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := reload(t, m).GetCurrentStore(); got != "c" {
+		t.Errorf("current store after rename = %q, want c", got)
+	}
+
+	// Removing it clears the selection
+	if err := m.RemoveStore("c"); err != nil {
+		t.Fatal(err)
+	}
+	if got := reload(t, m).GetCurrentStore(); got != "" {
+		t.Errorf("current store after removal = %q, want none", got)
+	}
+
+	m.SetCurrentStore("b")
+	if err := m.SetCurrentStore(""); err != nil {
+		t.Fatalf("SetCurrentStore(\"\") error = %v", err)
+	}
+	if got := reload(t, m).GetCurrentStore(); got != "" {
+		t.Errorf("current store after clearing = %q, want none", got)
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	home := t.TempDir()
+	xdg := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name     string
+		explicit string
+		env      string
+		xdg      string
+		want     string
+	}{
+		{
+			name: "home default",
+			want: filepath.Join(home, ".config", "shopify-theme-manager", "config.json"),
+		},
+		{
+			name: "xdg config home",
+			xdg:  xdg,
+			want: filepath.Join(xdg, "shopify-theme-manager", "config.json"),
+		},
+		{
+			name: "relative xdg config home is ignored",
+			xdg:  "relative/dir",
+			want: filepath.Join(home, ".config", "shopify-theme-manager", "config.json"),
+		},
+		{
+			name: "env overrides xdg",
+			env:  "/etc/stm/config.json",
+			xdg:  xdg,
+			want: "/etc/stm/config.json",
+		},
+		{
+			name:     "flag overrides env",
+			explicit: "/tmp/flag.json",
+			env:      "/etc/stm/config.json",
+			xdg:      xdg,
+			want:     "/tmp/flag.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ConfigEnvVar, tt.env)
+			t.Setenv(xdgConfigHomeEnvVar, tt.xdg)
+
+			got, err := ResolvePath(tt.explicit)
+			if err != nil {
+				t.Fatalf("ResolvePath() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolvePath() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewManagerAt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "stm.json")
+
+	m, err := NewManagerAt(path)
+	if err != nil {
+		t.Fatalf("NewManagerAt() error = %v", err)
+	}
+	if m.ConfigPath() != path {
+		t.Errorf("ConfigPath() = %s, want %s", m.ConfigPath(), path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("config file not created: %v", err)
+	}
+}
+
+func TestNewManagerAt_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte("{bad"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewManagerAt(path)
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) {
+		t.Fatalf("NewManagerAt() error = %v, want LoadError", err)
+	}
+	if !strings.Contains(err.Error(), "failed to read config "+path) {
+		t.Errorf("error = %q, want the config path", err)
+	}
+
+	m := loadErr.Manager
+	if m.ConfigPath() != path {
+		t.Errorf("ConfigPath() = %s, want %s", m.ConfigPath(), path)
+	}
+	if stores := m.ListStores(); len(stores) != 0 {
+		t.Errorf("ListStores() = %v, want none", stores)
+	}
+	if err := m.SetCurrentStore(""); err == nil {
+		t.Error("SetCurrentStore() succeeded on a corrupt config")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "{bad" {
+		t.Errorf("config file was changed to %q", data)
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"path/filepath"
+)
+
+// StoresForDir returns the stores whose project directory is dir or one of
+// its parents. Only the stores with the deepest matching project directory
+// are returned, so a project nested inside another wins; more than one
+// result means several stores share that directory. Project directories
+// that cannot be made absolute, because they are relative and no workspace
+// is set, never match.
+func StoresForDir(stores []Store, workspace, dir string) []Store {
+	dir = canonicalPath(dir)
+
+	projects := make(map[string][]Store)
+	for _, store := range stores {
+		path := store.ProjectPath(workspace)
+		if !filepath.IsAbs(path) {
+			continue
+		}
+		path = canonicalPath(path)
+		projects[path] = append(projects[path], store)
+	}
+
+	for {
+		if matches := projects[dir]; len(matches) > 0 {
+			return matches
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// canonicalPath cleans path and resolves symlinks when it exists, so the
+// same directory reached through a link compares equal.
+func canonicalPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
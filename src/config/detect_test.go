@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestStoresForDir(t *testing.T) {
+	workspace := t.TempDir()
+	for _, dir := range []string{"acme/sections", "globex", "globex/preview/assets", "shared"} {
+		if err := os.MkdirAll(filepath.Join(workspace, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outside := t.TempDir()
+
+	stores := []Store{
+		{Alias: "acme", ProjectDir: "acme"},
+		{Alias: "globex", ProjectDir: "globex"},
+		{Alias: "globex-preview", ProjectDir: "globex/preview"},
+		{Alias: "shared-a", ProjectDir: "shared"},
+		{Alias: "shared-b", ProjectDir: filepath.Join(workspace, "shared")},
+		{Alias: "elsewhere", ProjectDir: outside},
+	}
+
+	tests := []struct {
+		name string
+		dir  string
+		want []string
+	}{
+		{name: "project root", dir: filepath.Join(workspace, "acme"), want: []string{"acme"}},
+		{name: "nested directory", dir: filepath.Join(workspace, "acme", "sections"), want: []string{"acme"}},
+		{name: "nested project wins", dir: filepath.Join(workspace, "globex", "preview", "assets"), want: []string{"globex-preview"}},
+		{name: "outer project", dir: filepath.Join(workspace, "globex"), want: []string{"globex"}},
+		{name: "shared directory", dir: filepath.Join(workspace, "shared"), want: []string{"shared-a", "shared-b"}},
+		{name: "absolute project dir", dir: outside, want: []string{"elsewhere"}},
+		{name: "workspace itself", dir: workspace, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, store := range StoresForDir(stores, workspace, tt.dir) {
+				got = append(got, store.Alias)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StoresForDir(%s) = %v, want %v", tt.dir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoresForDir_Symlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need extra privileges on windows")
+	}
+	workspace := t.TempDir()
+	if err := os.Mkdir(filepath.Join(workspace, "acme"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(t.TempDir(), "acme-link")
+	if err := os.Symlink(filepath.Join(workspace, "acme"), link); err != nil {
+		t.Fatal(err)
+	}
+
+	got := StoresForDir([]Store{{Alias: "acme", ProjectDir: "acme"}}, workspace, link)
+	if len(got) != 1 || got[0].Alias != "acme" {
+		t.Errorf("StoresForDir(link) = %+v, want acme", got)
+	}
+}
+
+func TestStoresForDir_NoWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	if got := StoresForDir([]Store{{Alias: "acme", ProjectDir: "."}}, "", dir); got != nil {
+		t.Errorf("relative project dir without workspace matched: %+v", got)
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrDirNotFound = errors.New("directory not found")
+	ErrNotDir      = errors.New("not a directory")
+)
+
+// CheckDir checks that path is an existing directory. A missing directory
+// is reported with ErrDirNotFound, so callers can offer to create it.
+func CheckDir(path string) error {
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		return &ValidationError{
+			Field:  "dir",
+			Value:  path,
+			Reason: fmt.Sprintf("directory %s does not exist", path),
+			Err:    ErrDirNotFound,
+		}
+	case err != nil:
+		return err
+	case !info.IsDir():
+		return &ValidationError{
+			Field:  "dir",
+			Value:  path,
+			Reason: fmt.Sprintf("%s is not a directory", path),
+			Err:    ErrNotDir,
+		}
+	}
+	return nil
+}
+
+// ProjectDirWarnings reports problems with a store's project directory that
+// don't stop it from working: an absolute path inside the workspace, which
+// breaks when the workspace moves, a directory outside the workspace, and
+// a relative path without a workspace to resolve it against.
+func ProjectDirWarnings(workspace, projectDir string) []string {
+	if workspace == "" {
+		if filepath.IsAbs(projectDir) {
+			return nil
+		}
+		return []string{fmt.Sprintf("no workspace is set, so project directory %s is resolved against the current directory", projectDir)}
+	}
+
+	path := (&Store{ProjectDir: projectDir}).ProjectPath(workspace)
+	rel, inside := relativeTo(workspace, path)
+	switch {
+	case !inside:
+		return []string{fmt.Sprintf("project directory %s is outside the workspace %s", path, workspace)}
+	case filepath.IsAbs(projectDir):
+		return []string{fmt.Sprintf("project directory %s is inside the workspace; use the relative path %s so it moves with the workspace", projectDir, rel)}
+	}
+	return nil
+}
+
+// relativeTo returns path relative to dir and whether path is dir or lies
+// below it.
+func relativeTo(dir, path string) (string, bool) {
+	rel, err := filepath.Rel(canonicalPath(dir), canonicalPath(path))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// StoresSharingDir returns the stores, other than the one with alias skip,
+// whose project directory resolves to dir.
+func StoresSharingDir(stores []Store, workspace, dir, skip string) []Store {
+	dir = canonicalPath(dir)
+
+	var sharing []Store
+	for _, store := range stores {
+		if store.Alias == skip {
+			continue
+		}
+		if canonicalPath(store.ProjectPath(workspace)) == dir {
+			sharing = append(sharing, store)
+		}
+	}
+	return sharing
+}
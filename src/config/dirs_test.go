@@ -0,0 +1,138 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckDir(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckDir(dir); err != nil {
+		t.Errorf("CheckDir(dir) error = %v", err)
+	}
+	if err := CheckDir(filepath.Join(dir, "missing")); !errors.Is(err, ErrDirNotFound) {
+		t.Errorf("CheckDir(missing) error = %v, want ErrDirNotFound", err)
+	}
+	if err := CheckDir(file); !errors.Is(err, ErrNotDir) {
+		t.Errorf("CheckDir(file) error = %v, want ErrNotDir", err)
+	}
+}
+
+func TestProjectDirWarnings(t *testing.T) {
+	workspace := t.TempDir()
+	outside := t.TempDir()
+
+	tests := []struct {
+		name       string
+		workspace  string
+		projectDir string
+		want       string
+	}{
+		{name: "relative inside", workspace: workspace, projectDir: "acme"},
+		{name: "workspace itself", workspace: workspace, projectDir: "."},
+		{name: "absolute inside", workspace: workspace, projectDir: filepath.Join(workspace, "acme"), want: "use the relative path acme"},
+		{name: "absolute outside", workspace: workspace, projectDir: outside, want: "is outside the workspace"},
+		{name: "relative outside", workspace: workspace, projectDir: filepath.Join("..", "elsewhere"), want: "is outside the workspace"},
+		{name: "dotted name inside", workspace: workspace, projectDir: "..acme"},
+		{name: "no workspace", projectDir: "acme", want: "no workspace is set"},
+		{name: "no workspace, absolute", projectDir: outside},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := ProjectDirWarnings(tt.workspace, tt.projectDir)
+			if tt.want == "" {
+				if len(warnings) > 0 {
+					t.Errorf("ProjectDirWarnings() = %v, want none", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || !strings.Contains(warnings[0], tt.want) {
+				t.Errorf("ProjectDirWarnings() = %v, want a warning containing %q", warnings, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoresSharingDir(t *testing.T) {
+	workspace := t.TempDir()
+	shared := filepath.Join(workspace, "shared")
+	if err := os.Mkdir(shared, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	stores := []Store{
+		{Alias: "a", ProjectDir: "shared"},
+		{Alias: "b", ProjectDir: shared},
+		{Alias: "c", ProjectDir: "shared/"},
+		{Alias: "d", ProjectDir: "other"},
+	}
+
+	var got []string
+	for _, store := range StoresSharingDir(stores, workspace, shared, "a") {
+		got = append(got, store.Alias)
+	}
+	if want := []string{"b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("StoresSharingDir() = %v, want %v", got, want)
+	}
+}
+
+func TestSetWorkspace_MissingDir(t *testing.T) {
+	m := newTestManager(t)
+	workspace := m.GetWorkspace()
+	if err := m.SetWorkspace(filepath.Join(t.TempDir(), "missing")); !errors.Is(err, ErrDirNotFound) {
+		t.Errorf("SetWorkspace() error = %v, want ErrDirNotFound", err)
+	}
+	if m.GetWorkspace() != workspace {
+		t.Errorf("workspace = %q after a failed SetWorkspace", m.GetWorkspace())
+	}
+}
+
+func TestAddStore_MissingDir(t *testing.T) {
+	m := newTestManager(t)
+	if err := m.AddStore("store-a", "a", "missing"); !errors.Is(err, ErrDirNotFound) {
+		t.Errorf("AddStore() error = %v, want ErrDirNotFound", err)
+	}
+	file := filepath.Join(m.GetWorkspace(), "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddStore("store-a", "a", file); !errors.Is(err, ErrNotDir) {
+		t.Errorf("AddStore() error = %v, want ErrNotDir", err)
+	}
+	if stores := m.ListStores(); len(stores) != 0 {
+		t.Errorf("stores = %v after a failed AddStore", stores)
+	}
+}
+
+func TestUpdateStore_MissingDir(t *testing.T) {
+	m := newTestManager(t)
+	if err := m.AddStore("store-a", "a", "dir-a"); err != nil {
+		t.Fatal(err)
+	}
+
+	store := *m.GetStore("a")
+	store.ProjectDir = "missing"
+	if err := m.UpdateStore("a", store); !errors.Is(err, ErrDirNotFound) {
+		t.Errorf("UpdateStore() error = %v, want ErrDirNotFound", err)
+	}
+
+	// Other changes still work after the directory is gone
+	if err := os.Remove(filepath.Join(m.GetWorkspace(), "dir-a")); err != nil {
+		t.Fatal(err)
+	}
+	store = *m.GetStore("a")
+	store.Protected = true
+	if err := m.UpdateStore("a", store); err != nil {
+		t.Errorf("UpdateStore() error = %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path"
+)
+
+var ErrInvalidIgnorePattern = errors.New("invalid ignore pattern")
+
+// ValidateIgnorePattern checks a glob passed to the Shopify CLI's --ignore
+// flag, such as "config/settings_data.json" or "templates/*.json".
+func ValidateIgnorePattern(pattern string) error {
+	reason := ""
+	if pattern == "" {
+		reason = "ignore pattern cannot be empty"
+	} else if _, err := path.Match(pattern, ""); err != nil {
+		reason = fmt.Sprintf("invalid ignore pattern %q: %v", pattern, err)
+	}
+	if reason == "" {
+		return nil
+	}
+	return &ValidationError{
+		Field:  "ignore",
+		Value:  pattern,
+		Reason: reason,
+		Err:    ErrInvalidIgnorePattern,
+	}
+}
+
+// AddIgnorePatterns appends default ignore patterns to a store. Patterns
+// the store already has are skipped.
+func (m *ConfigManager) AddIgnorePatterns(alias string, patterns ...string) error {
+	for _, pattern := range patterns {
+		if err := ValidateIgnorePattern(pattern); err != nil {
+			return err
+		}
+	}
+
+	return m.update(func(config *Config) error {
+		index := m.storeIndex(alias)
+		if index < 0 {
+			return fmt.Errorf("store with alias %q not found", alias)
+		}
+		store := &config.Stores[index]
+		for _, pattern := range patterns {
+			if !containsString(store.Ignore, pattern) {
+				store.Ignore = append(store.Ignore, pattern)
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveIgnorePatterns removes default ignore patterns from a store. Every
+// pattern must be present.
+func (m *ConfigManager) RemoveIgnorePatterns(alias string, patterns ...string) error {
+	return m.update(func(config *Config) error {
+		index := m.storeIndex(alias)
+		if index < 0 {
+			return fmt.Errorf("store with alias %q not found", alias)
+		}
+		store := &config.Stores[index]
+		for _, pattern := range patterns {
+			if !containsString(store.Ignore, pattern) {
+				return fmt.Errorf("store %q has no ignore pattern %q", alias, pattern)
+			}
+		}
+
+		kept := store.Ignore[:0]
+		for _, pattern := range store.Ignore {
+			if !containsString(patterns, pattern) {
+				kept = append(kept, pattern)
+			}
+		}
+		store.Ignore = kept
+		if len(store.Ignore) == 0 {
+			store.Ignore = nil
+		}
+		return nil
+	})
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
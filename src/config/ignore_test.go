@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateIgnorePattern(t *testing.T) {
+	for _, pattern := range []string{"config/settings_data.json", "templates/*.json", "locales/*"} {
+		if err := ValidateIgnorePattern(pattern); err != nil {
+			t.Errorf("ValidateIgnorePattern(%q) error = %v", pattern, err)
+		}
+	}
+	for _, pattern := range []string{"", "templates/[.json", `assets/\`} {
+		if err := ValidateIgnorePattern(pattern); !errors.Is(err, ErrInvalidIgnorePattern) {
+			t.Errorf("ValidateIgnorePattern(%q) error = %v, want ErrInvalidIgnorePattern", pattern, err)
+		}
+	}
+}
+
+func TestIgnorePatterns(t *testing.T) {
+	m := newTestManager(t)
+	if err := m.AddStore("store-a", "a", "dir-a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.AddIgnorePatterns("a", "config/settings_data.json", "locales/*"); err != nil {
+		t.Fatalf("AddIgnorePatterns() error = %v", err)
+	}
+	// Patterns already present are not duplicated
+	if err := m.AddIgnorePatterns("a", "locales/*"); err != nil {
+		t.Fatalf("AddIgnorePatterns() error = %v", err)
+	}
+
+	want := []string{"config/settings_data.json", "locales/*"}
+	if got := reload(t, m).GetStore("a").Ignore; !reflect.DeepEqual(got, want) {
+		t.Errorf("saved ignore = %v, want %v", got, want)
+	}
+
+	// Stores handed out are copies
+	m.GetStore("a").Ignore[0] = "changed"
+	if got := m.GetStore("a").Ignore[0]; got != "config/settings_data.json" {
+		t.Errorf("mutating a returned store changed config: ignore[0] = %s", got)
+	}
+
+	if err := m.RemoveIgnorePatterns("a", "locales/*", "missing/*"); err == nil {
+		t.Error("RemoveIgnorePatterns() with a missing pattern succeeded")
+	}
+	if got := m.GetStore("a").Ignore; !reflect.DeepEqual(got, want) {
+		t.Errorf("failed removal changed ignore to %v", got)
+	}
+
+	if err := m.RemoveIgnorePatterns("a", "locales/*", "config/settings_data.json"); err != nil {
+		t.Fatalf("RemoveIgnorePatterns() error = %v", err)
+	}
+	if got := reload(t, m).GetStore("a").Ignore; got != nil {
+		t.Errorf("saved ignore = %v, want none", got)
+	}
+
+	if err := m.AddIgnorePatterns("a", "templates/["); !errors.Is(err, ErrInvalidIgnorePattern) {
+		t.Errorf("AddIgnorePatterns() invalid pattern error = %v", err)
+	}
+	if err := m.AddIgnorePatterns("missing", "locales/*"); err == nil {
+		t.Error("AddIgnorePatterns() on missing store succeeded")
+	}
+}
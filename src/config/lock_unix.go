@@ -0,0 +1,31 @@
+//go:build !windows
+
+package config
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileLock is an advisory lock held on a sidecar lock file.
+type fileLock struct {
+	file *os.File
+}
+
+// lockFile blocks until an exclusive advisory lock on path is acquired.
+func lockFile(path string) (*fileLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &fileLock{file: file}, nil
+}
+
+func (l *fileLock) unlock() error {
+	defer l.file.Close()
+	return syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+}
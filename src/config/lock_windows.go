@@ -0,0 +1,45 @@
+//go:build windows
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	lockRetryInterval = 10 * time.Millisecond
+	lockTimeout       = 10 * time.Second
+)
+
+// fileLock is held by exclusively creating a sidecar lock file.
+type fileLock struct {
+	path string
+	file *os.File
+}
+
+// lockFile blocks until the lock file at path can be created, or fails
+// after lockTimeout in case a crashed process left it behind.
+func lockFile(path string) (*fileLock, error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0644)
+		if err == nil {
+			return &fileLock{path: path, file: file}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for config lock %s; remove it if no other stm process is running", path)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+func (l *fileLock) unlock() error {
+	l.file.Close()
+	return os.Remove(l.path)
+}
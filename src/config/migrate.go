@@ -0,0 +1,193 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CurrentVersion is the config schema version written by this build of stm.
+const CurrentVersion = 6
+
+// Migration upgrades a raw config document from version From to From+1.
+// Migrations work on the decoded JSON rather than Config so that they can
+// read fields that no longer exist in the current schema.
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(doc map[string]interface{}) error
+}
+
+// migrations is the ordered registry of schema upgrades. Append a new entry
+// and bump CurrentVersion whenever the shape of Config or Store changes.
+var migrations = []Migration{
+	{
+		From:        0,
+		Description: "add schema version and normalize store IDs to *.myshopify.com domains",
+		Apply:       migrateV0ToV1,
+	},
+	{
+		From:        1,
+		Description: "add named theme environments to stores",
+		Apply:       migrateV1ToV2,
+	},
+	{
+		From:        2,
+		Description: "add live-theme protection flag to stores",
+		Apply:       migrateV2ToV3,
+	},
+	{
+		From:        3,
+		Description: "add default ignore patterns to stores",
+		Apply:       migrateV3ToV4,
+	},
+	{
+		From:        4,
+		Description: "add tags to stores",
+		Apply:       migrateV4ToV5,
+	},
+	{
+		From:        5,
+		Description: "add the current store selected with stm use",
+		Apply:       migrateV5ToV6,
+	},
+}
+
+// MigrationPlan describes the upgrade of the config file on disk to
+// CurrentVersion.
+type MigrationPlan struct {
+	FromVersion int
+	ToVersion   int
+	Steps       []Migration
+	// Result is the config file content after migrating.
+	Result []byte
+}
+
+// documentVersion returns the schema version of a raw config document.
+// Files written before versioning was introduced have no version field.
+func documentVersion(doc map[string]interface{}) (int, error) {
+	raw, ok := doc["version"]
+	if !ok || raw == nil {
+		return 0, nil
+	}
+	version, ok := raw.(float64)
+	if !ok || version != float64(int(version)) || version < 0 {
+		return 0, fmt.Errorf("invalid config version %v", raw)
+	}
+	return int(version), nil
+}
+
+// migrateDocument applies every migration needed to bring doc up to
+// CurrentVersion and returns the steps that were applied.
+func migrateDocument(doc map[string]interface{}) ([]Migration, error) {
+	version, err := documentVersion(doc)
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("config version %d is newer than this stm supports (%d); please upgrade stm", version, CurrentVersion)
+	}
+
+	var applied []Migration
+	for version < CurrentVersion {
+		migration, ok := findMigration(version)
+		if !ok {
+			return nil, fmt.Errorf("no migration registered from config version %d", version)
+		}
+		if err := migration.Apply(doc); err != nil {
+			return nil, fmt.Errorf("migrating config from version %d: %w", version, err)
+		}
+		version++
+		doc["version"] = version
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+func findMigration(from int) (Migration, bool) {
+	for _, migration := range migrations {
+		if migration.From == from {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+// decodeConfig parses config file content, migrating it in memory to
+// CurrentVersion. It reports the version found in the file and the
+// migrations that were applied.
+func decodeConfig(data []byte) (*Config, int, []Migration, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, 0, nil, err
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+
+	fromVersion, err := documentVersion(doc)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	applied, err := migrateDocument(doc)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	var config Config
+	if err := json.Unmarshal(migrated, &config); err != nil {
+		return nil, 0, nil, err
+	}
+	if config.Stores == nil {
+		config.Stores = []Store{}
+	}
+	return &config, fromVersion, applied, nil
+}
+
+// migrateV0ToV1 normalizes store IDs saved before AddStore validated them.
+// IDs that cannot be normalized are left untouched.
+func migrateV0ToV1(doc map[string]interface{}) error {
+	stores, _ := doc["stores"].([]interface{})
+	for _, raw := range stores {
+		store, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		storeID, _ := store["storeId"].(string)
+		if normalized, err := NormalizeStoreID(storeID); err == nil {
+			store["storeId"] = normalized
+		}
+	}
+	return nil
+}
+
+// migrateV1ToV2 introduces Store.Themes. The field is optional, so no data
+// changes; the version bump stops older stm builds from dropping it.
+func migrateV1ToV2(doc map[string]interface{}) error {
+	return nil
+}
+
+// migrateV2ToV3 introduces Store.Protected, which defaults to false.
+func migrateV2ToV3(doc map[string]interface{}) error {
+	return nil
+}
+
+// migrateV3ToV4 introduces Store.Ignore, which defaults to no patterns.
+func migrateV3ToV4(doc map[string]interface{}) error {
+	return nil
+}
+
+// migrateV4ToV5 introduces Store.Tags, which defaults to no tags.
+func migrateV4ToV5(doc map[string]interface{}) error {
+	return nil
+}
+
+// migrateV5ToV6 introduces Config.CurrentStore, which defaults to no store.
+func migrateV5ToV6(doc map[string]interface{}) error {
+	return nil
+}
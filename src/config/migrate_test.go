@@ -0,0 +1,114 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+const legacyConfig = `{
+  "stores": [
+    {"storeId": "My-Store", "alias": "store1", "projectDir": "store1-theme"},
+    {"storeId": "https://bad.example.com", "alias": "store2", "projectDir": "store2-theme"}
+  ],
+  "workspace": "/tmp/workspace"
+}`
+
+// writeLegacyConfig replaces m's config file with an unversioned one.
+func writeLegacyConfig(t *testing.T, m *ConfigManager) {
+	t.Helper()
+	if err := os.WriteFile(m.configPath, []byte(legacyConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfig_MigratesInMemory(t *testing.T) {
+	m := newTestManager(t)
+	writeLegacyConfig(t, m)
+
+	loaded := reload(t, m)
+	if loaded.config.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", loaded.config.Version, CurrentVersion)
+	}
+	if store := loaded.GetStore("store1"); store == nil || store.StoreID != "my-store.myshopify.com" {
+		t.Errorf("GetStore(store1) = %+v, want normalized store ID", store)
+	}
+	if store := loaded.GetStore("store2"); store == nil || store.StoreID != "https://bad.example.com" {
+		t.Errorf("GetStore(store2) = %+v, want unnormalizable store ID left alone", store)
+	}
+
+	// Loading alone does not rewrite the file
+	data, _ := os.ReadFile(m.configPath)
+	if string(data) != legacyConfig {
+		t.Error("config file was rewritten on load")
+	}
+}
+
+func TestMigrationPlan(t *testing.T) {
+	m := newTestManager(t)
+
+	plan, err := m.MigrationPlan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Steps) != 0 {
+		t.Errorf("fresh config has %d pending migrations, want 0", len(plan.Steps))
+	}
+
+	writeLegacyConfig(t, m)
+	plan, err = m.MigrationPlan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.FromVersion != 0 || plan.ToVersion != CurrentVersion {
+		t.Errorf("plan versions = %d -> %d, want 0 -> %d", plan.FromVersion, plan.ToVersion, CurrentVersion)
+	}
+	if len(plan.Steps) != CurrentVersion {
+		t.Errorf("len(Steps) = %d, want %d", len(plan.Steps), CurrentVersion)
+	}
+	if !strings.Contains(string(plan.Result), "my-store.myshopify.com") {
+		t.Errorf("Result = %s, want normalized store ID", plan.Result)
+	}
+}
+
+func TestMigrate_BacksUpOldVersion(t *testing.T) {
+	m := newTestManager(t)
+	writeLegacyConfig(t, m)
+
+	if err := m.Migrate(); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	backup, err := os.ReadFile(m.configPath + ".v0.bak")
+	if err != nil {
+		t.Fatalf("reading versioned backup: %v", err)
+	}
+	if string(backup) != legacyConfig {
+		t.Error("versioned backup does not match the original file")
+	}
+
+	data, err := os.ReadFile(m.configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved Config
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Version != CurrentVersion {
+		t.Errorf("saved Version = %d, want %d", saved.Version, CurrentVersion)
+	}
+}
+
+func TestLoadConfig_NewerVersion(t *testing.T) {
+	m := newTestManager(t)
+	if err := os.WriteFile(m.configPath, []byte(`{"version": 999, "stores": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := m.loadConfig()
+	if err == nil || !strings.Contains(err.Error(), "newer than this stm supports") {
+		t.Errorf("loadConfig() error = %v, want newer version error", err)
+	}
+}
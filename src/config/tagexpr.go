@@ -0,0 +1,188 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TagExpr selects stores by their tags. Expressions combine tags with
+// "!" (not), "&" (and), "|" or "," (or) and parentheses; the words
+// "not", "and" and "or" may be used instead. "!" binds tightest and "|"
+// loosest, so "base:dawn & !tier:plus | client:acme" means
+// "(base:dawn and not tier:plus) or client:acme".
+type TagExpr interface {
+	Match(store *Store) bool
+	String() string
+}
+
+type tagTerm string
+
+func (t tagTerm) Match(store *Store) bool { return store.HasTag(string(t)) }
+func (t tagTerm) String() string          { return string(t) }
+
+type tagNot struct{ expr TagExpr }
+
+func (n tagNot) Match(store *Store) bool { return !n.expr.Match(store) }
+func (n tagNot) String() string          { return "!" + n.expr.String() }
+
+type tagAnd struct{ left, right TagExpr }
+
+func (a tagAnd) Match(store *Store) bool { return a.left.Match(store) && a.right.Match(store) }
+func (a tagAnd) String() string          { return "(" + a.left.String() + " & " + a.right.String() + ")" }
+
+type tagOr struct{ left, right TagExpr }
+
+func (o tagOr) Match(store *Store) bool { return o.left.Match(store) || o.right.Match(store) }
+func (o tagOr) String() string          { return "(" + o.left.String() + " | " + o.right.String() + ")" }
+
+// ParseTagExpr parses a tag expression such as "client:acme",
+// "base:dawn & !tier:plus" or "client:acme, client:globex".
+func ParseTagExpr(input string) (TagExpr, error) {
+	tokens, err := tokenizeTagExpr(input)
+	if err != nil {
+		return nil, tagExprError(input, err.Error())
+	}
+	if len(tokens) == 0 {
+		return nil, tagExprError(input, "expression is empty")
+	}
+
+	p := &tagExprParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, tagExprError(input, err.Error())
+	}
+	if p.pos < len(p.tokens) {
+		return nil, tagExprError(input, fmt.Sprintf("unexpected %q", p.tokens[p.pos]))
+	}
+	return expr, nil
+}
+
+func tagExprError(input, reason string) error {
+	return &ValidationError{
+		Field:  "tagExpr",
+		Value:  input,
+		Reason: fmt.Sprintf("invalid tag expression %q: %s", input, reason),
+		Err:    ErrInvalidTagExpr,
+	}
+}
+
+// tokenizeTagExpr splits an expression into operators and words, mapping
+// the keyword operators to their symbols.
+func tokenizeTagExpr(input string) ([]string, error) {
+	var tokens []string
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("()!&|,", r):
+			token := string(r)
+			if token == "," {
+				token = "|"
+			}
+			tokens = append(tokens, token)
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("()!&|,", runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			switch strings.ToLower(word) {
+			case "not":
+				word = "!"
+			case "and":
+				word = "&"
+			case "or":
+				word = "|"
+			default:
+				if err := ValidateTag(word); err != nil {
+					return nil, fmt.Errorf("%q is not a valid tag", word)
+				}
+			}
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens, nil
+}
+
+type tagExprParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *tagExprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *tagExprParser) parseOr() (TagExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "|" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = tagOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *tagExprParser) parseAnd() (TagExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&" {
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = tagAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *tagExprParser) parseNot() (TagExpr, error) {
+	if p.peek() == "!" {
+		p.pos++
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return tagNot{expr}, nil
+	}
+	return p.parseTerm()
+}
+
+func (p *tagExprParser) parseTerm() (TagExpr, error) {
+	token := p.peek()
+	switch token {
+	case "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case "(":
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return expr, nil
+	case ")", "&", "|":
+		return nil, fmt.Errorf("unexpected %q", token)
+	}
+	p.pos++
+	return tagTerm(token), nil
+}
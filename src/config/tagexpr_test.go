@@ -0,0 +1,60 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTagExpr(t *testing.T) {
+	acme := &Store{Alias: "acme", Tags: []string{"client:acme", "base:dawn", "tier:plus"}}
+	globex := &Store{Alias: "globex", Tags: []string{"client:globex", "base:dawn"}}
+	initech := &Store{Alias: "initech", Tags: []string{"client:initech", "base:craft"}}
+	stores := []*Store{acme, globex, initech}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{expr: "base:dawn", want: []string{"acme", "globex"}},
+		{expr: "!base:dawn", want: []string{"initech"}},
+		{expr: "base:dawn & !tier:plus", want: []string{"globex"}},
+		{expr: "base:dawn and not tier:plus", want: []string{"globex"}},
+		{expr: "client:acme, client:initech", want: []string{"acme", "initech"}},
+		{expr: "client:acme | client:globex & tier:plus", want: []string{"acme"}},
+		{expr: "(client:acme | client:globex) & !tier:plus", want: []string{"globex"}},
+		{expr: "!!base:craft", want: []string{"initech"}},
+		{expr: "tier:enterprise", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := ParseTagExpr(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseTagExpr() error = %v", err)
+			}
+
+			var got []string
+			for _, store := range stores {
+				if expr.Match(store) {
+					got = append(got, store.Alias)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("%s matched %v, want %v", expr, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("%s matched %v, want %v", expr, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTagExpr_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "   ", "base:dawn &", "& base:dawn", "(base:dawn", "base:dawn)", "base:dawn tier:plus", "Base:Dawn", "!"} {
+		if _, err := ParseTagExpr(expr); !errors.Is(err, ErrInvalidTagExpr) {
+			t.Errorf("ParseTagExpr(%q) error = %v, want ErrInvalidTagExpr", expr, err)
+		}
+	}
+}
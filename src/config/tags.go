@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxTagLength bounds tags so they stay readable in tables
+const maxTagLength = 64
+
+var (
+	ErrInvalidTag     = errors.New("invalid tag")
+	ErrInvalidTagExpr = errors.New("invalid tag expression")
+)
+
+// ValidateTag checks a store tag. Tags are lowercase letters, digits, '.',
+// '-', '_' and ':', starting with a letter or digit, such as "client:acme"
+// or "tier:plus". The tag expression keywords "and", "or" and "not" are
+// reserved.
+func ValidateTag(tag string) error {
+	reason := ""
+	switch {
+	case tag == "":
+		reason = "tag cannot be empty"
+	case len(tag) > maxTagLength:
+		reason = fmt.Sprintf("invalid tag %q: tags are at most %d characters", tag, maxTagLength)
+	case !isTagStart(rune(tag[0])) || strings.IndexFunc(tag, func(r rune) bool { return !isTagChar(r) }) >= 0:
+		reason = fmt.Sprintf("invalid tag %q: use lowercase letters, digits, '.', '-', '_' and ':'", tag)
+	case tag == "and" || tag == "or" || tag == "not":
+		reason = fmt.Sprintf("invalid tag %q: reserved for tag expressions", tag)
+	}
+	if reason == "" {
+		return nil
+	}
+	return &ValidationError{
+		Field:  "tag",
+		Value:  tag,
+		Reason: reason,
+		Err:    ErrInvalidTag,
+	}
+}
+
+func isTagStart(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+}
+
+func isTagChar(r rune) bool {
+	return isTagStart(r) || r == '.' || r == '-' || r == '_' || r == ':'
+}
+
+// AddTags tags a store. Tags the store already has are skipped.
+func (m *ConfigManager) AddTags(alias string, tags ...string) error {
+	for _, tag := range tags {
+		if err := ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+
+	return m.update(func(config *Config) error {
+		index := m.storeIndex(alias)
+		if index < 0 {
+			return fmt.Errorf("store with alias %q not found", alias)
+		}
+		store := &config.Stores[index]
+		for _, tag := range tags {
+			if !store.HasTag(tag) {
+				store.Tags = append(store.Tags, tag)
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveTags removes tags from a store. Every tag must be present.
+func (m *ConfigManager) RemoveTags(alias string, tags ...string) error {
+	return m.update(func(config *Config) error {
+		index := m.storeIndex(alias)
+		if index < 0 {
+			return fmt.Errorf("store with alias %q not found", alias)
+		}
+		store := &config.Stores[index]
+		for _, tag := range tags {
+			if !store.HasTag(tag) {
+				return fmt.Errorf("store %q has no tag %q", alias, tag)
+			}
+		}
+
+		kept := store.Tags[:0]
+		for _, tag := range store.Tags {
+			if !containsString(tags, tag) {
+				kept = append(kept, tag)
+			}
+		}
+		store.Tags = kept
+		if len(store.Tags) == 0 {
+			store.Tags = nil
+		}
+		return nil
+	})
+}
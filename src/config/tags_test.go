@@ -0,0 +1,61 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateTag(t *testing.T) {
+	for _, tag := range []string{"client:acme", "base:dawn", "tier:plus", "v2", "eu-west_1.shop"} {
+		if err := ValidateTag(tag); err != nil {
+			t.Errorf("ValidateTag(%q) error = %v", tag, err)
+		}
+	}
+	for _, tag := range []string{"", "Client:Acme", "-draft", ":acme", "has space", "a&b", "not", strings.Repeat("a", 65)} {
+		if err := ValidateTag(tag); !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("ValidateTag(%q) error = %v, want ErrInvalidTag", tag, err)
+		}
+	}
+}
+
+func TestTags(t *testing.T) {
+	m := newTestManager(t)
+	if err := m.AddStore("store-a", "a", "dir-a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.AddTags("a", "client:acme", "base:dawn"); err != nil {
+		t.Fatalf("AddTags() error = %v", err)
+	}
+	// Tags already present are not duplicated
+	if err := m.AddTags("a", "base:dawn"); err != nil {
+		t.Fatalf("AddTags() error = %v", err)
+	}
+
+	want := []string{"client:acme", "base:dawn"}
+	if got := reload(t, m).GetStore("a").Tags; !reflect.DeepEqual(got, want) {
+		t.Errorf("saved tags = %v, want %v", got, want)
+	}
+
+	if err := m.RemoveTags("a", "client:acme"); err != nil {
+		t.Fatalf("RemoveTags() error = %v", err)
+	}
+	if err := m.RemoveTags("a", "client:acme"); err == nil {
+		t.Error("RemoveTags() on missing tag succeeded")
+	}
+	if err := m.RemoveTags("a", "base:dawn"); err != nil {
+		t.Fatalf("RemoveTags() error = %v", err)
+	}
+	if got := reload(t, m).GetStore("a").Tags; got != nil {
+		t.Errorf("saved tags = %v, want none", got)
+	}
+
+	if err := m.AddTags("a", "Client"); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("AddTags() invalid tag error = %v", err)
+	}
+	if err := m.AddTags("missing", "client:acme"); err == nil {
+		t.Error("AddTags() on missing store succeeded")
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// LiveTheme is the theme environment value that targets the store's
+// published theme, whatever its ID.
+const LiveTheme = "live"
+
+var (
+	ErrInvalidThemeEnv = errors.New("invalid theme environment")
+	ErrInvalidThemeID  = errors.New("invalid theme ID")
+)
+
+// ValidateThemeEnv checks a theme environment name. The same characters
+// as aliases are allowed.
+func ValidateThemeEnv(env string) error {
+	if err := ValidateAlias(env); err != nil {
+		return &ValidationError{
+			Field:  "themeEnv",
+			Value:  env,
+			Reason: fmt.Sprintf("invalid theme environment %q: use letters, digits, '.', '-' and '_'", env),
+			Err:    ErrInvalidThemeEnv,
+		}
+	}
+	return nil
+}
+
+// ValidateThemeID checks that a theme environment value is either a
+// numeric theme ID or LiveTheme.
+func ValidateThemeID(themeID string) error {
+	invalid := &ValidationError{
+		Field:  "themeId",
+		Value:  themeID,
+		Reason: fmt.Sprintf("invalid theme ID %q: use a numeric theme ID or %q", themeID, LiveTheme),
+		Err:    ErrInvalidThemeID,
+	}
+	if themeID == LiveTheme {
+		return nil
+	}
+	if themeID == "" {
+		return invalid
+	}
+	for _, r := range themeID {
+		if r < '0' || r > '9' {
+			return invalid
+		}
+	}
+	return nil
+}
+
+// SetThemeEnv maps a named theme environment of a store to a theme ID.
+func (m *ConfigManager) SetThemeEnv(alias, env, themeID string) error {
+	if err := ValidateThemeEnv(env); err != nil {
+		return err
+	}
+	if err := ValidateThemeID(themeID); err != nil {
+		return err
+	}
+
+	return m.update(func(config *Config) error {
+		index := m.storeIndex(alias)
+		if index < 0 {
+			return fmt.Errorf("store with alias %q not found", alias)
+		}
+		store := &config.Stores[index]
+		if store.Themes == nil {
+			store.Themes = make(map[string]string)
+		}
+		store.Themes[env] = themeID
+		return nil
+	})
+}
+
+// UnsetThemeEnv removes a named theme environment from a store.
+func (m *ConfigManager) UnsetThemeEnv(alias, env string) error {
+	return m.update(func(config *Config) error {
+		index := m.storeIndex(alias)
+		if index < 0 {
+			return fmt.Errorf("store with alias %q not found", alias)
+		}
+		store := &config.Stores[index]
+		if _, ok := store.Themes[env]; !ok {
+			return fmt.Errorf("store %q has no theme environment %q", alias, env)
+		}
+		delete(store.Themes, env)
+		if len(store.Themes) == 0 {
+			store.Themes = nil
+		}
+		return nil
+	})
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateThemeID(t *testing.T) {
+	for _, id := range []string{"123456", LiveTheme} {
+		if err := ValidateThemeID(id); err != nil {
+			t.Errorf("ValidateThemeID(%q) error = %v", id, err)
+		}
+	}
+	for _, id := range []string{"", "dawn", "12a", "-1", "Live"} {
+		if err := ValidateThemeID(id); !errors.Is(err, ErrInvalidThemeID) {
+			t.Errorf("ValidateThemeID(%q) error = %v, want ErrInvalidThemeID", id, err)
+		}
+	}
+}
+
+func TestThemeEnvs(t *testing.T) {
+	m := newTestManager(t)
+	if err := m.AddStore("store-a", "a", "dir-a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.SetThemeEnv("a", "staging", "123"); err != nil {
+		t.Fatalf("SetThemeEnv() error = %v", err)
+	}
+	if err := m.SetThemeEnv("a", "production", LiveTheme); err != nil {
+		t.Fatalf("SetThemeEnv() error = %v", err)
+	}
+
+	want := map[string]string{"staging": "123", "production": LiveTheme}
+	if got := reload(t, m).GetStore("a").Themes; !reflect.DeepEqual(got, want) {
+		t.Errorf("saved themes = %v, want %v", got, want)
+	}
+
+	// Stores handed out are copies
+	m.GetStore("a").Themes["staging"] = "999"
+	if got := m.GetStore("a").Themes["staging"]; got != "123" {
+		t.Errorf("mutating a returned store changed config: staging = %s", got)
+	}
+
+	if err := m.UnsetThemeEnv("a", "staging"); err != nil {
+		t.Fatalf("UnsetThemeEnv() error = %v", err)
+	}
+	if err := m.UnsetThemeEnv("a", "production"); err != nil {
+		t.Fatalf("UnsetThemeEnv() error = %v", err)
+	}
+	if got := reload(t, m).GetStore("a").Themes; got != nil {
+		t.Errorf("saved themes = %v, want none", got)
+	}
+
+	if err := m.UnsetThemeEnv("a", "staging"); err == nil {
+		t.Error("UnsetThemeEnv() on missing environment succeeded")
+	}
+	if err := m.SetThemeEnv("missing", "staging", "123"); err == nil {
+		t.Error("SetThemeEnv() on missing store succeeded")
+	}
+	if err := m.SetThemeEnv("a", "bad env", "123"); !errors.Is(err, ErrInvalidThemeEnv) {
+		t.Errorf("SetThemeEnv() error = %v, want ErrInvalidThemeEnv", err)
+	}
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const shopifyDomainSuffix = ".myshopify.com"
+
+// Sentinel errors wrapped by ValidationError, for use with errors.Is
+var (
+	ErrDuplicateAlias   = errors.New("duplicate alias")
+	ErrDuplicateStoreID = errors.New("duplicate store ID")
+	ErrInvalidStoreID   = errors.New("invalid store ID")
+	ErrInvalidAlias     = errors.New("invalid alias")
+)
+
+// ValidationError describes why a store field was rejected.
+type ValidationError struct {
+	Field  string
+	Value  string
+	Reason string
+	Err    error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Reason
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// NormalizeStoreID trims and lowercases a store domain, appending
+// .myshopify.com to bare store handles such as "my-store". URLs with a
+// scheme, path or port are rejected.
+func NormalizeStoreID(storeID string) (string, error) {
+	id := strings.ToLower(strings.TrimSpace(storeID))
+	invalid := func(reason string) error {
+		return &ValidationError{
+			Field:  "storeId",
+			Value:  storeID,
+			Reason: reason,
+			Err:    ErrInvalidStoreID,
+		}
+	}
+
+	if id == "" {
+		return "", invalid("store ID cannot be empty")
+	}
+	if strings.Contains(id, "://") {
+		return "", invalid(fmt.Sprintf("store ID %q must be a domain, not a URL", storeID))
+	}
+	if strings.ContainsAny(id, "/?#:@") {
+		return "", invalid(fmt.Sprintf("store ID %q must not contain a path, port or query", storeID))
+	}
+
+	if !strings.Contains(id, ".") {
+		id += shopifyDomainSuffix
+	}
+
+	for _, label := range strings.Split(id, ".") {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return "", invalid(fmt.Sprintf("store ID %q is not a valid domain", storeID))
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return "", invalid(fmt.Sprintf("store ID %q contains invalid character %q", storeID, r))
+			}
+		}
+	}
+
+	return id, nil
+}
+
+// ValidateAlias checks that an alias is safe to type as a shell argument:
+// letters, digits, '.', '-' and '_' only, not starting with '-'.
+func ValidateAlias(alias string) error {
+	invalid := func(reason string) error {
+		return &ValidationError{
+			Field:  "alias",
+			Value:  alias,
+			Reason: reason,
+			Err:    ErrInvalidAlias,
+		}
+	}
+
+	if alias == "" {
+		return invalid("alias cannot be empty")
+	}
+	if strings.HasPrefix(alias, "-") {
+		return invalid(fmt.Sprintf("alias %q must not start with '-'", alias))
+	}
+	for _, r := range alias {
+		if unicode.IsSpace(r) {
+			return invalid(fmt.Sprintf("alias %q must not contain whitespace", alias))
+		}
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.') {
+			return invalid(fmt.Sprintf("alias %q contains invalid character %q", alias, r))
+		}
+	}
+	return nil
+}
+
+func duplicateAliasError(alias string) error {
+	return &ValidationError{
+		Field:  "alias",
+		Value:  alias,
+		Reason: fmt.Sprintf("store with alias %q already exists", alias),
+		Err:    ErrDuplicateAlias,
+	}
+}
+
+func duplicateStoreIDError(storeID, alias string) error {
+	return &ValidationError{
+		Field:  "storeId",
+		Value:  storeID,
+		Reason: fmt.Sprintf("store %q is already configured as %q", storeID, alias),
+		Err:    ErrDuplicateStoreID,
+	}
+}
+
+// validateStore normalizes the store ID and checks the store against every
+// configured store except the one at index skip.
+func (m *ConfigManager) validateStore(store *Store, skip int) error {
+	storeID, err := NormalizeStoreID(store.StoreID)
+	if err != nil {
+		return err
+	}
+	store.StoreID = storeID
+
+	if err := ValidateAlias(store.Alias); err != nil {
+		return err
+	}
+
+	for i, existing := range m.config.Stores {
+		if i == skip {
+			continue
+		}
+		if existing.Alias == store.Alias {
+			return duplicateAliasError(store.Alias)
+		}
+		if existingID, err := NormalizeStoreID(existing.StoreID); err == nil && existingID == storeID {
+			return duplicateStoreIDError(storeID, existing.Alias)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeStoreID(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "bare handle", input: "my-store", want: "my-store.myshopify.com"},
+		{name: "full domain", input: "my-store.myshopify.com", want: "my-store.myshopify.com"},
+		{name: "mixed case and whitespace", input: "  My-Store  ", want: "my-store.myshopify.com"},
+		{name: "custom domain", input: "shop.example.com", want: "shop.example.com"},
+		{name: "empty", input: "   ", wantErr: true},
+		{name: "url with scheme", input: "https://my-store.myshopify.com", wantErr: true},
+		{name: "path", input: "my-store.myshopify.com/admin", wantErr: true},
+		{name: "port", input: "my-store.myshopify.com:443", wantErr: true},
+		{name: "underscore", input: "my_store", wantErr: true},
+		{name: "empty label", input: "my-store..com", wantErr: true},
+		{name: "leading hyphen", input: "-store", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeStoreID(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidStoreID) {
+					t.Errorf("NormalizeStoreID(%q) error = %v, want ErrInvalidStoreID", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeStoreID(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeStoreID(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAlias(t *testing.T) {
+	tests := []struct {
+		alias   string
+		wantErr bool
+	}{
+		{alias: "store1"},
+		{alias: "acme_prod"},
+		{alias: "acme-staging.v2"},
+		{alias: "", wantErr: true},
+		{alias: "my store", wantErr: true},
+		{alias: "store\t1", wantErr: true},
+		{alias: "-store", wantErr: true},
+		{alias: "store;rm", wantErr: true},
+		{alias: "$(whoami)", wantErr: true},
+		{alias: "store/1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			err := ValidateAlias(tt.alias)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("ValidateAlias(%q) error = %v, wantErr %v", tt.alias, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidAlias) {
+				t.Errorf("ValidateAlias(%q) error = %v, want ErrInvalidAlias", tt.alias, err)
+			}
+		})
+	}
+}
+
+func TestAddStoreValidation(t *testing.T) {
+	m := newTestManager(t)
+	if err := m.AddStore("my-store", "store1", "dir"); err != nil {
+		t.Fatalf("AddStore() error = %v", err)
+	}
+	if store := m.GetStore("store1"); store == nil || store.StoreID != "my-store.myshopify.com" {
+		t.Errorf("GetStore() = %+v, want normalized store ID", store)
+	}
+
+	tests := []struct {
+		name    string
+		storeID string
+		alias   string
+		wantErr error
+	}{
+		{name: "duplicate alias", storeID: "other-store", alias: "store1", wantErr: ErrDuplicateAlias},
+		{name: "duplicate store ID", storeID: "MY-STORE.myshopify.com", alias: "store2", wantErr: ErrDuplicateStoreID},
+		{name: "malformed store ID", storeID: "https://other-store.myshopify.com", alias: "store2", wantErr: ErrInvalidStoreID},
+		{name: "invalid alias", storeID: "other-store", alias: "store 2", wantErr: ErrInvalidAlias},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.AddStore(tt.storeID, tt.alias, "dir")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddStore() error = %v, want %v", err, tt.wantErr)
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("AddStore() error = %T, want *ValidationError", err)
+			}
+		})
+	}
+
+	if got := len(m.ListStores()); got != 1 {
+		t.Errorf("len(ListStores()) = %d, want 1", got)
+	}
+}
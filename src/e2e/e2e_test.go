@@ -0,0 +1,473 @@
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const themeListJSON = `[
+  {"id": 222, "name": "Dawn staging", "role": "unpublished"},
+  {"id": 111, "name": "Dawn", "role": "live"}
+]`
+
+// addStore registers store1 with a project directory under the workspace.
+func addStore(h *harness) string {
+	h.t.Helper()
+	projectDir := filepath.Join(h.workspace, "store1-theme")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		h.t.Fatal(err)
+	}
+	h.mustRun("set-workspace", h.workspace)
+	h.mustRun("add", "--store-id", "my-store", "--alias", "store1", "--project-dir", "store1-theme")
+	return projectDir
+}
+
+func TestAddAndListStores(t *testing.T) {
+	h := newHarness(t)
+	addStore(h)
+
+	cfg := h.config()
+	if cfg.Workspace != h.workspace {
+		t.Errorf("workspace = %s, want %s", cfg.Workspace, h.workspace)
+	}
+	if len(cfg.Stores) != 1 {
+		t.Fatalf("stores = %+v, want one store", cfg.Stores)
+	}
+	if got := cfg.Stores[0]; got.Alias != "store1" || got.StoreID != "my-store.myshopify.com" || got.ProjectDir != "store1-theme" {
+		t.Errorf("store = %+v", got)
+	}
+
+	res := h.mustRun("stores", "--output", "json")
+	var stores []map[string]interface{}
+	if err := json.Unmarshal([]byte(res.stdout), &stores); err != nil {
+		t.Fatalf("stm stores output is not JSON: %v\n%s", err, res.stdout)
+	}
+	if len(stores) != 1 || stores[0]["dirExists"] != true {
+		t.Errorf("stores = %v", stores)
+	}
+
+	if invocations := h.invocations(); len(invocations) != 0 {
+		t.Errorf("shopify ran %d times, want 0", len(invocations))
+	}
+}
+
+func TestAddWithoutInputFails(t *testing.T) {
+	h := newHarness(t)
+
+	res := h.run("add", "--store-id", "my-store")
+	if res.exitCode == 0 {
+		t.Fatal("stm add without --project-dir succeeded, want failure")
+	}
+	if !strings.Contains(res.stderr, "--project-dir is required") {
+		t.Errorf("stderr = %q, want missing flag message", res.stderr)
+	}
+	if stores := h.config().Stores; len(stores) != 0 {
+		t.Errorf("stores = %+v, want none", stores)
+	}
+}
+
+func TestScanRegistersThemes(t *testing.T) {
+	h := newHarness(t)
+	addStore(h)
+	themeDir := filepath.Join(h.workspace, "clients", "acme")
+	if err := os.MkdirAll(filepath.Join(themeDir, "layout"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(themeDir, "layout", "theme.liquid"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	toml := "[environments.production]\nstore = \"acme\"\n"
+	if err := os.WriteFile(filepath.Join(themeDir, "shopify.theme.toml"), []byte(toml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h.mustRun("scan", "--yes")
+
+	stores := h.config().Stores
+	if len(stores) != 2 {
+		t.Fatalf("stores = %+v, want store1 and acme", stores)
+	}
+	acme := stores[1]
+	if acme.Alias != "acme" || acme.StoreID != "acme.myshopify.com" || acme.ProjectDir != filepath.Join("clients", "acme") {
+		t.Errorf("scanned store = %+v", acme)
+	}
+
+	// Scanning again finds nothing new
+	res := h.mustRun("scan", "--yes")
+	if !strings.Contains(res.stdout, "No new theme directories") {
+		t.Errorf("stdout = %q, want nothing to register", res.stdout)
+	}
+}
+
+func TestListThemes(t *testing.T) {
+	h := newHarness(t)
+	addStore(h)
+	h.script(fakeCommand{Args: []string{"theme", "list"}, Stdout: themeListJSON})
+
+	res := h.mustRun("list", "store1")
+
+	want := "ID   NAME          ROLE\n" +
+		"111  Dawn          live\n" +
+		"222  Dawn staging  unpublished\n"
+	if res.stdout != want {
+		t.Errorf("stdout = %q, want %q", res.stdout, want)
+	}
+
+	invocations := h.invocations()
+	if len(invocations) != 1 {
+		t.Fatalf("shopify ran %d times, want 1", len(invocations))
+	}
+	wantArgs := []string{"theme", "list", "--store", "my-store.myshopify.com", "--json"}
+	if !reflect.DeepEqual(invocations[0].Args, wantArgs) {
+		t.Errorf("shopify args = %v, want %v", invocations[0].Args, wantArgs)
+	}
+}
+
+func TestListThemes_CLIFailure(t *testing.T) {
+	h := newHarness(t)
+	addStore(h)
+	h.script(fakeCommand{
+		Args:     []string{"theme", "list"},
+		Stderr:   "You are not logged in\n",
+		ExitCode: 3,
+	})
+
+	res := h.run("list", "store1")
+	if res.exitCode == 0 {
+		t.Fatal("stm list succeeded, want failure")
+	}
+	if !strings.Contains(res.stderr, "You are not logged in") {
+		t.Errorf("stderr = %q, want CLI error", res.stderr)
+	}
+}
+
+func TestListThemes_SlowCLI(t *testing.T) {
+	h := newHarness(t)
+	addStore(h)
+	h.script(fakeCommand{Args: []string{"theme", "list"}, Stdout: themeListJSON, DelayMS: 300})
+
+	start := time.Now()
+	res := h.mustRun("list", "store1", "--output", "json")
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("stm list returned after %v, before the CLI finished", elapsed)
+	}
+	if !strings.Contains(res.stdout, `"name": "Dawn"`) {
+		t.Errorf("stdout = %q, want theme JSON", res.stdout)
+	}
+}
+
+func TestDevRunsInProjectDirectory(t *testing.T) {
+	h := newHarness(t)
+	projectDir := addStore(h)
+	h.script(
+		fakeCommand{Args: []string{"theme", "list"}, Stdout: themeListJSON},
+		fakeCommand{Args: []string{"theme", "dev"}, Stdout: "Serving on http://127.0.0.1:9292\n"},
+	)
+
+	res := h.mustRun("dev", "store1", "222", "--port", "9292")
+	if !strings.Contains(res.stdout, "Serving on") {
+		t.Errorf("stdout = %q, want CLI output passed through", res.stdout)
+	}
+
+	// The theme list is checked first to guard against writing to the live theme
+	invocations := h.invocations()
+	if len(invocations) != 2 {
+		t.Fatalf("shopify ran %d times, want 2", len(invocations))
+	}
+	dev := invocations[1]
+	wantArgs := []string{"theme", "dev", "--store", "my-store.myshopify.com", "--theme", "222", "--port", "9292"}
+	if !reflect.DeepEqual(dev.Args, wantArgs) {
+		t.Errorf("shopify args = %v, want %v", dev.Args, wantArgs)
+	}
+	gotDir, _ := filepath.EvalSymlinks(dev.Dir)
+	wantDir, _ := filepath.EvalSymlinks(projectDir)
+	if gotDir != wantDir {
+		t.Errorf("shopify ran in %s, want %s", dev.Dir, projectDir)
+	}
+}
+
+func TestDevRefusesLiveTheme(t *testing.T) {
+	h := newHarness(t)
+	addStore(h)
+	h.script(
+		fakeCommand{Args: []string{"theme", "list"}, Stdout: themeListJSON},
+		fakeCommand{Args: []string{"theme", "dev"}, Stdout: "Serving on http://127.0.0.1:9292\n"},
+	)
+
+	res := h.run("dev", "store1", "111")
+	if res.exitCode == 0 {
+		t.Fatal("stm dev succeeded against the live theme, want failure")
+	}
+	if !strings.Contains(res.stderr, "--allow-live") {
+		t.Errorf("stderr = %q, want --allow-live hint", res.stderr)
+	}
+
+	// Protected stores refuse even with --allow-live
+	h.mustRun("protect", "store1")
+	res = h.run("dev", "store1", "111", "--allow-live")
+	if !strings.Contains(res.stderr, "protected store") {
+		t.Errorf("stderr = %q, want protected store refusal", res.stderr)
+	}
+
+	for _, inv := range h.invocations() {
+		if inv.Args[1] == "dev" {
+			t.Errorf("dev server started against the live theme: %v", inv.Args)
+		}
+	}
+}
+
+func TestPushWithDefaultIgnore(t *testing.T) {
+	h := newHarness(t)
+	projectDir := addStore(h)
+	h.mustRun("ignore", "add", "store1", "config/settings_data.json")
+	h.script(
+		fakeCommand{Args: []string{"theme", "list"}, Stdout: themeListJSON},
+		fakeCommand{Args: []string{"theme", "push"}, Stdout: "Pushed\n"},
+	)
+
+	h.mustRun("push", "store1", "--theme", "222", "--only", "sections/*")
+
+	invocations := h.invocations()
+	if len(invocations) != 2 {
+		t.Fatalf("shopify ran %d times, want 2", len(invocations))
+	}
+	push := invocations[1]
+	wantArgs := []string{"theme", "push", "--store", "my-store.myshopify.com", "--theme", "222",
+		"--only", "sections/*", "--ignore", "config/settings_data.json"}
+	if !reflect.DeepEqual(push.Args, wantArgs) {
+		t.Errorf("shopify args = %v, want %v", push.Args, wantArgs)
+	}
+	gotDir, _ := filepath.EvalSymlinks(push.Dir)
+	wantDir, _ := filepath.EvalSymlinks(projectDir)
+	if gotDir != wantDir {
+		t.Errorf("shopify ran in %s, want %s", push.Dir, projectDir)
+	}
+}
+
+func TestEachContinuesPastFailures(t *testing.T) {
+	h := newHarness(t)
+	addStore(h)
+	if err := os.MkdirAll(filepath.Join(h.workspace, "store2-theme"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	h.mustRun("add", "--store-id", "other-store", "--alias", "store2", "--project-dir", "store2-theme")
+	h.script(
+		fakeCommand{Args: []string{"theme", "check", "--store", "my-store.myshopify.com"}, Stderr: "2 offenses\n", ExitCode: 1},
+		fakeCommand{Args: []string{"theme", "check"}, Stdout: "No offenses\n"},
+	)
+
+	res := h.run("each", "--", "theme", "check")
+	if res.exitCode == 0 {
+		t.Fatal("stm each succeeded, want failure")
+	}
+	if !strings.Contains(res.stdout, "store2 | No offenses") {
+		t.Errorf("stdout = %q, want prefixed CLI output", res.stdout)
+	}
+	if !strings.Contains(res.stderr, "store1 | 2 offenses") {
+		t.Errorf("stderr = %q, want prefixed CLI errors", res.stderr)
+	}
+	if !strings.Contains(res.stderr, "1 of 2 stores failed") {
+		t.Errorf("stderr = %q, want failure count", res.stderr)
+	}
+	if invocations := h.invocations(); len(invocations) != 2 {
+		t.Errorf("shopify ran %d times, want 2", len(invocations))
+	}
+}
+
+func TestDevExitStatus(t *testing.T) {
+	h := newHarness(t)
+	addStore(h)
+	h.script(fakeCommand{Args: []string{"theme", "dev"}, Stderr: "Port 9292 is in use\n", ExitCode: 2})
+
+	res := h.run("dev", "store1")
+	if res.exitCode == 0 {
+		t.Fatal("stm dev succeeded, want failure")
+	}
+	if !strings.Contains(res.stderr, "Port 9292 is in use") {
+		t.Errorf("stderr = %q, want CLI stderr passed through", res.stderr)
+	}
+}
+
+func TestCurrentStore(t *testing.T) {
+	h := newHarness(t)
+	addStore(h)
+	h.script(fakeCommand{Args: []string{"theme", "list"}, Stdout: themeListJSON})
+
+	h.mustRun("use", "store1")
+	if cfg := h.config(); cfg.CurrentStore != "store1" {
+		t.Errorf("currentStore = %q, want store1", cfg.CurrentStore)
+	}
+
+	h.mustRun("list")
+	invocations := h.invocations()
+	if len(invocations) != 1 || invocations[0].Args[3] != "my-store.myshopify.com" {
+		t.Errorf("invocations = %+v, want theme list for store1", invocations)
+	}
+
+	res := h.mustRun("status")
+	if !strings.Contains(res.stdout, "store1 (from stm use)") {
+		t.Errorf("stdout = %q, want current store", res.stdout)
+	}
+}
+
+func TestStoreFromWorkingDirectory(t *testing.T) {
+	h := newHarness(t)
+	projectDir := addStore(h)
+	sections := filepath.Join(projectDir, "sections")
+	if err := os.Mkdir(sections, 0755); err != nil {
+		t.Fatal(err)
+	}
+	h.script(fakeCommand{Args: []string{"theme", "list"}, Stdout: themeListJSON})
+
+	res := h.runIn(sections, "list")
+	if res.exitCode != 0 {
+		t.Fatalf("stm list failed: %s", res.stderr)
+	}
+	invocations := h.invocations()
+	if len(invocations) != 1 || invocations[0].Args[3] != "my-store.myshopify.com" {
+		t.Errorf("invocations = %+v, want theme list for store1", invocations)
+	}
+
+	// Outside every project directory there is nothing to fall back on
+	res = h.run("list")
+	if res.exitCode == 0 || !strings.Contains(res.stderr, "is not inside a store's project directory") {
+		t.Errorf("stm list from the workspace: exit %d, stderr %q", res.exitCode, res.stderr)
+	}
+}
+
+func TestUnknownStore(t *testing.T) {
+	h := newHarness(t)
+
+	res := h.run("list", "missing")
+	if res.exitCode == 0 {
+		t.Fatal("stm list succeeded, want failure")
+	}
+	if !strings.Contains(res.stderr, `store with alias "missing" not found`) {
+		t.Errorf("stderr = %q, want not found message", res.stderr)
+	}
+	if invocations := h.invocations(); len(invocations) != 0 {
+		t.Errorf("shopify ran %d times, want 0", len(invocations))
+	}
+}
+
+func TestOldCLIFailsEarly(t *testing.T) {
+	h := newHarness(t)
+	addStore(h)
+	h.script(
+		fakeCommand{Args: []string{"version"}, Stdout: "2.15.6\n"},
+		fakeCommand{Args: []string{"theme", "list"}, Stdout: themeListJSON},
+	)
+
+	res := h.run("list", "store1")
+	if res.exitCode == 0 {
+		t.Fatal("stm list succeeded, want failure")
+	}
+	if !strings.Contains(res.stderr, "2.15.6 is installed, stm needs 3.0.0 or later; upgrade with") {
+		t.Errorf("stderr = %q, want upgrade message", res.stderr)
+	}
+	if invocations := h.invocations(); len(invocations) != 0 {
+		t.Errorf("shopify ran %d times, want 0", len(invocations))
+	}
+}
+
+func TestMissingFlagFailsEarly(t *testing.T) {
+	h := newHarness(t)
+	addStore(h)
+	h.script(
+		fakeCommand{Args: []string{"theme", "list", "--help"}, Stdout: "FLAGS\n      --name=<value>\n  -s, --store=<value>\n"},
+		fakeCommand{Args: []string{"theme", "list"}, Stdout: themeListJSON},
+	)
+
+	res := h.run("list", "store1")
+	if res.exitCode == 0 {
+		t.Fatal("stm list succeeded, want failure")
+	}
+	if !strings.Contains(res.stderr, "the installed shopify theme list has no --json flag; upgrade with") {
+		t.Errorf("stderr = %q, want upgrade message", res.stderr)
+	}
+	if invocations := h.invocations(); len(invocations) != 0 {
+		t.Errorf("shopify ran %d times, want 0", len(invocations))
+	}
+}
+
+func TestNewCLIMajorVersion(t *testing.T) {
+	h := newHarness(t)
+	addStore(h)
+	h.script(
+		fakeCommand{Args: []string{"version"}, Stdout: "4.0.0\n"},
+		fakeCommand{Args: []string{"theme", "list"}, Stdout: themeListJSON},
+	)
+
+	res := h.run("list", "store1")
+	if res.exitCode == 0 {
+		t.Fatal("stm list succeeded, want failure")
+	}
+	if !strings.Contains(res.stderr, "STM_SKIP_CLI_CHECK=1") {
+		t.Errorf("stderr = %q, want a hint to skip the check", res.stderr)
+	}
+
+	h.extraEnv = []string{"STM_SKIP_CLI_CHECK=1"}
+	h.mustRun("list", "store1")
+	if invocations := h.invocations(); len(invocations) != 1 {
+		t.Errorf("shopify ran %d times, want 1", len(invocations))
+	}
+}
+
+func TestCLIVersionIsCached(t *testing.T) {
+	h := newHarness(t)
+	addStore(h)
+	h.script(fakeCommand{Args: []string{"theme", "list"}, Stdout: themeListJSON})
+
+	h.mustRun("list", "store1")
+	h.mustRun("list", "store1")
+
+	checks := len(h.allInvocations()) - len(h.invocations())
+	if checks != 2 {
+		t.Errorf("shopify version and theme list --help ran %d times, want 2", checks)
+	}
+}
+
+func TestDoctorWithCorruptConfig(t *testing.T) {
+	h := newHarness(t)
+	configPath := filepath.Join(h.home, ".config", "shopify-theme-manager", "config.json")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte("{bad"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	res := h.run("doctor")
+	if res.exitCode == 0 {
+		t.Fatal("stm doctor succeeded, want failure")
+	}
+	for _, want := range []string{"FAIL  Config file", "failed to read config " + configPath, "hint: fix the file, or restore the last good copy from " + configPath + ".bak"} {
+		if !strings.Contains(res.stdout, want) {
+			t.Errorf("stdout = %q, want it to contain %q", res.stdout, want)
+		}
+	}
+	if strings.Contains(res.stdout, "Workspace") {
+		t.Errorf("stdout = %q, want no workspace check without a config", res.stdout)
+	}
+
+	res = h.mustRun("config", "path")
+	if strings.TrimSpace(res.stdout) != configPath {
+		t.Errorf("config path = %q, want %s", res.stdout, configPath)
+	}
+
+	res = h.run("stores")
+	if res.exitCode == 0 {
+		t.Fatal("stm stores succeeded, want failure")
+	}
+	if !strings.Contains(res.stderr, "run stm doctor for help") {
+		t.Errorf("stderr = %q, want a hint to run stm doctor", res.stderr)
+	}
+	if data, _ := os.ReadFile(configPath); string(data) != "{bad" {
+		t.Errorf("config file was changed to %q", data)
+	}
+}
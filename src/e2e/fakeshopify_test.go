@@ -0,0 +1,116 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment variables read by the fake shopify executable
+const (
+	fixtureEnvVar = "FAKE_SHOPIFY_FIXTURE"
+	logEnvVar     = "FAKE_SHOPIFY_LOG"
+)
+
+// fakeCommand scripts the fake CLI's response to one command. It matches
+// any invocation whose arguments start with Args.
+type fakeCommand struct {
+	Args     []string `json:"args"`
+	Stdout   string   `json:"stdout,omitempty"`
+	Stderr   string   `json:"stderr,omitempty"`
+	ExitCode int      `json:"exitCode,omitempty"`
+	DelayMS  int      `json:"delayMs,omitempty"`
+}
+
+// fakeFixture is the file the fake CLI reads its script from.
+type fakeFixture struct {
+	Commands []fakeCommand `json:"commands"`
+}
+
+// fakeInvocation is appended to the log file for every run of the fake CLI.
+type fakeInvocation struct {
+	Args []string `json:"args"`
+	Dir  string   `json:"dir"`
+}
+
+// runFakeShopify is the main function of the fake shopify executable. The
+// e2e test binary is installed on PATH as "shopify" and TestMain dispatches
+// here when started under that name.
+func runFakeShopify(args []string) int {
+	if err := logInvocation(args); err != nil {
+		fmt.Fprintf(os.Stderr, "fake shopify: %v\n", err)
+		return 125
+	}
+
+	fixture, err := readFixture()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fake shopify: %v\n", err)
+		return 125
+	}
+
+	for _, command := range fixture.Commands {
+		if !hasPrefix(args, command.Args) {
+			continue
+		}
+		time.Sleep(time.Duration(command.DelayMS) * time.Millisecond)
+		fmt.Fprint(os.Stdout, command.Stdout)
+		fmt.Fprint(os.Stderr, command.Stderr)
+		return command.ExitCode
+	}
+
+	fmt.Fprintf(os.Stderr, "fake shopify: no fixture for %q\n", strings.Join(args, " "))
+	return 127
+}
+
+func readFixture() (*fakeFixture, error) {
+	var fixture fakeFixture
+	path := os.Getenv(fixtureEnvVar)
+	if path == "" {
+		return &fixture, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+func logInvocation(args []string) error {
+	path := os.Getenv(logEnvVar)
+	if path == "" {
+		return nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(fakeInvocation{Args: args, Dir: dir})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+func hasPrefix(args, prefix []string) bool {
+	if len(prefix) > len(args) {
+		return false
+	}
+	for i := range prefix {
+		if args[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,295 @@
+package e2e
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/colinxr/shopify-theme-manager/config"
+)
+
+// stmBinary is the stm executable built once by TestMain
+var stmBinary string
+
+func TestMain(m *testing.M) {
+	// Started as the fake Shopify CLI rather than as the test binary
+	if strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe") == "shopify" {
+		os.Exit(runFakeShopify(os.Args[1:]))
+	}
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "stm-e2e")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	stmBinary = filepath.Join(dir, "stm"+exeSuffix())
+	build := exec.Command("go", "build", "-o", stmBinary, "..")
+	build.Stdout = os.Stdout
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "building stm: %v\n", err)
+		return 1
+	}
+
+	return m.Run()
+}
+
+func exeSuffix() string {
+	if runtime.GOOS == "windows" {
+		return ".exe"
+	}
+	return ""
+}
+
+// harness runs the real stm binary against a temporary HOME, with the
+// fake shopify executable first on PATH.
+type harness struct {
+	t         *testing.T
+	home      string
+	workspace string
+	binDir    string
+	fixture   string
+	log       string
+	// extraEnv is added to the environment of every stm run
+	extraEnv []string
+}
+
+// result is the outcome of one stm invocation
+type result struct {
+	stdout   string
+	stderr   string
+	exitCode int
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("end-to-end test")
+	}
+
+	root := t.TempDir()
+	h := &harness{
+		t:         t,
+		home:      filepath.Join(root, "home"),
+		workspace: filepath.Join(root, "workspace"),
+		binDir:    filepath.Join(root, "bin"),
+		fixture:   filepath.Join(root, "fixture.json"),
+		log:       filepath.Join(root, "shopify.log"),
+	}
+	for _, dir := range []string{h.home, h.workspace, h.binDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h.installFakeShopify()
+	h.script()
+	return h
+}
+
+// installFakeShopify copies the test binary onto PATH as "shopify".
+func (h *harness) installFakeShopify() {
+	h.t.Helper()
+	self, err := os.Executable()
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	src, err := os.Open(self)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(filepath.Join(h.binDir, "shopify"+exeSuffix()), os.O_CREATE|os.O_WRONLY, 0755)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		h.t.Fatal(err)
+	}
+	if err := dst.Close(); err != nil {
+		h.t.Fatal(err)
+	}
+}
+
+// fakeCLIVersion is what the fake CLI reports for "shopify version" unless
+// a test scripts another answer.
+const fakeCLIVersion = "3.58.2"
+
+// fakeCLIHelp is the flags part of what the fake CLI prints for
+// "shopify <command> --help", for the commands stm runs itself.
+var fakeCLIHelp = map[string]string{
+	"theme list": "FLAGS\n      --json\n      --name=<value>\n  -s, --store=<value>\n",
+	"theme dev":  "FLAGS\n      --port=<value>\n  -s, --store=<value>\n  -t, --theme=<value>\n",
+	"theme push": "FLAGS\n  -a, --allow-live\n  -x, --ignore=<value>...\n  -n, --nodelete\n  -o, --only=<value>...\n  -s, --store=<value>\n  -t, --theme=<value>\n",
+	"theme pull": "FLAGS\n  -x, --ignore=<value>...\n  -n, --nodelete\n  -o, --only=<value>...\n  -s, --store=<value>\n  -t, --theme=<value>\n",
+}
+
+// script replaces the fake CLI's fixture with the given commands. The fake
+// CLI also answers "shopify version" with fakeCLIVersion and "--help" with
+// fakeCLIHelp; a test can script either itself to answer differently.
+func (h *harness) script(commands ...fakeCommand) {
+	h.t.Helper()
+	// Help comes first, as "theme list" would also match "theme list --help"
+	var help []fakeCommand
+	for command, flags := range fakeCLIHelp {
+		args := append(strings.Fields(command), "--help")
+		if !scripted(commands, args) {
+			help = append(help, fakeCommand{Args: args, Stdout: flags})
+		}
+	}
+	commands = append(append(help, commands...), fakeCommand{Args: []string{"version"}, Stdout: fakeCLIVersion + "\n"})
+	data, err := json.Marshal(fakeFixture{Commands: commands})
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	if err := os.WriteFile(h.fixture, data, 0644); err != nil {
+		h.t.Fatal(err)
+	}
+}
+
+// run executes stm with args from the workspace and returns its output and
+// exit status.
+func (h *harness) run(args ...string) result {
+	h.t.Helper()
+	return h.runIn(h.workspace, args...)
+}
+
+// runIn is like run but starts stm in dir.
+func (h *harness) runIn(dir string, args ...string) result {
+	h.t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(stmBinary, args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = h.env()
+
+	err := cmd.Run()
+	res := result{stdout: stdout.String(), stderr: stderr.String()}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		res.exitCode = exitErr.ExitCode()
+	default:
+		h.t.Fatalf("running stm: %v", err)
+	}
+	return res
+}
+
+// env returns a minimal environment so the developer's own config, cache
+// and Shopify CLI are never touched.
+func (h *harness) env() []string {
+	env := []string{
+		"HOME=" + h.home,
+		"USERPROFILE=" + h.home,
+		"XDG_CACHE_HOME=" + filepath.Join(h.home, ".cache"),
+		"PATH=" + h.binDir + string(os.PathListSeparator) + os.Getenv("PATH"),
+		fixtureEnvVar + "=" + h.fixture,
+		logEnvVar + "=" + h.log,
+	}
+	if runtime.GOOS == "windows" {
+		env = append(env, "SystemRoot="+os.Getenv("SystemRoot"))
+	}
+	return append(env, h.extraEnv...)
+}
+
+// scripted reports whether commands has a fixture for exactly args.
+func scripted(commands []fakeCommand, args []string) bool {
+	for _, command := range commands {
+		if strings.Join(command.Args, " ") == strings.Join(args, " ") {
+			return true
+		}
+	}
+	return false
+}
+
+// invocations returns every run of the fake CLI so far, except the
+// "shopify version" and "--help" runs stm makes to check the CLI.
+func (h *harness) invocations() []fakeInvocation {
+	h.t.Helper()
+	var invocations []fakeInvocation
+	for _, inv := range h.allInvocations() {
+		if !isCLICheck(inv.Args) {
+			invocations = append(invocations, inv)
+		}
+	}
+	return invocations
+}
+
+// isCLICheck reports whether args are a run stm makes to check the CLI
+func isCLICheck(args []string) bool {
+	if len(args) == 1 && args[0] == "version" {
+		return true
+	}
+	return len(args) > 0 && args[len(args)-1] == "--help"
+}
+
+// allInvocations returns every run of the fake CLI so far.
+func (h *harness) allInvocations() []fakeInvocation {
+	h.t.Helper()
+	file, err := os.Open(h.log)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	defer file.Close()
+
+	var invocations []fakeInvocation
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var inv fakeInvocation
+		if err := json.Unmarshal(scanner.Bytes(), &inv); err != nil {
+			h.t.Fatal(err)
+		}
+		invocations = append(invocations, inv)
+	}
+	if err := scanner.Err(); err != nil {
+		h.t.Fatal(err)
+	}
+	return invocations
+}
+
+// config reads the config file stm wrote under the temporary HOME.
+func (h *harness) config() config.Config {
+	h.t.Helper()
+	data, err := os.ReadFile(filepath.Join(h.home, ".config", "shopify-theme-manager", "config.json"))
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	var cfg config.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		h.t.Fatalf("config.json is not valid JSON: %v", err)
+	}
+	return cfg
+}
+
+// mustRun runs stm and fails the test unless it exits successfully.
+func (h *harness) mustRun(args ...string) result {
+	h.t.Helper()
+	res := h.run(args...)
+	if res.exitCode != 0 {
+		h.t.Fatalf("stm %s exited %d\nstdout: %s\nstderr: %s", strings.Join(args, " "), res.exitCode, res.stdout, res.stderr)
+	}
+	return res
+}
@@ -1,20 +1,40 @@
 package main
 
 import (
+	"errors"
 	"log"
+	"os"
 
 	"github.com/colinxr/shopify-theme-manager/commands"
 	"github.com/colinxr/shopify-theme-manager/config"
+	"github.com/colinxr/shopify-theme-manager/shopify"
 )
 
 func main() {
-	cfg, err := config.NewManager()
-	if err != nil {
+	// A config file that can't be read still lets stm doctor diagnose it
+	cfg, err := config.NewManagerAt(commands.ConfigPathFromArgs(os.Args[1:]))
+	var loadErr *config.LoadError
+	if errors.As(err, &loadErr) {
+		cfg = loadErr.Manager
+	} else if err != nil {
 		log.Fatal(err)
 	}
 
-	rootCmd := commands.NewRootCommand(cfg)
+	var runner shopify.Runner = shopify.NewRunner()
+	if os.Getenv(shopify.SkipCheckEnvVar) == "" {
+		cache := shopify.NewVersionCache(runner)
+		runner = &shopify.CheckedRunner{
+			Runner:  runner,
+			Version: cache.Version,
+			Flags:   cache.Flags,
+		}
+	}
+
+	rootCmd := commands.NewRootCommand(cfg, runner)
+	if loadErr != nil {
+		commands.RequireConfig(rootCmd, loadErr)
+	}
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatal(err)
 	}
-} 
\ No newline at end of file
+}
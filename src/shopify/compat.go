@@ -0,0 +1,161 @@
+package shopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// SkipCheckEnvVar turns off the Shopify CLI version check when set, for
+// trying a CLI release stm doesn't know yet.
+const SkipCheckEnvVar = "STM_SKIP_CLI_CHECK"
+
+// UpgradeHint tells the user how to get a newer Shopify CLI
+const UpgradeHint = "upgrade with: brew upgrade shopify-cli (or npm install -g @shopify/cli@latest)"
+
+var (
+	ErrCLITooOld        = errors.New("Shopify CLI is too old")
+	ErrCLITooNew        = errors.New("Shopify CLI is newer than stm supports")
+	ErrFlagNotSupported = errors.New("Shopify CLI doesn't have a flag stm passes")
+)
+
+// MinCLIVersion is the oldest Shopify CLI release stm works with. Version 2
+// was a different program, which ran themes with "theme serve" rather than
+// "theme dev".
+var MinCLIVersion = Version{Major: 3}
+
+// MaxCLIMajor is the newest major version of the Shopify CLI stm knows.
+// A new major version may rename or drop flags stm passes.
+const MaxCLIMajor = 3
+
+// droppableFlags are flags stm leaves out when the installed CLI doesn't
+// have them. Each only turns off a confirmation, which the CLI then asks
+// for itself, and takes no value.
+var droppableFlags = []string{"--allow-live"}
+
+// helpFlagPattern matches a flag at the start of a line of oclif help, such
+// as "  -s, --store=<value>  Store URL" or "      --[no-]color". Flags only
+// mentioned in a description don't match.
+var helpFlagPattern = regexp.MustCompile(`(?m)^\s+(?:-[A-Za-z0-9], )?--(\[no-\])?([a-z0-9][a-z0-9-]*)`)
+
+// CheckCLIVersion checks that stm supports the Shopify CLI release v.
+func CheckCLIVersion(v Version) error {
+	if v.Less(MinCLIVersion) {
+		return fmt.Errorf("%w: %s is installed, stm needs %s or later; %s", ErrCLITooOld, v, MinCLIVersion, UpgradeHint)
+	}
+	if v.Major > MaxCLIMajor {
+		return fmt.Errorf("%w: %s is installed, stm knows up to %d.x; update stm, or set %s=1 to try anyway", ErrCLITooNew, v, MaxCLIMajor, SkipCheckEnvVar)
+	}
+	return nil
+}
+
+// ParseHelpFlags returns the long flags listed in the output of
+// "shopify <command> --help".
+func ParseHelpFlags(help string) []string {
+	var flags []string
+	for _, match := range helpFlagPattern.FindAllStringSubmatch(help, -1) {
+		flags = append(flags, "--"+match[2])
+		if match[1] != "" {
+			flags = append(flags, "--no-"+match[2])
+		}
+	}
+	return flags
+}
+
+// AdaptArgs checks the long flags in args against flags, the ones the
+// installed CLI lists for the command, and returns the arguments to run
+// along with the flags it left out. A missing flag is left out when it is
+// one of droppableFlags and is an error otherwise. Flags after "--" are
+// not checked.
+func AdaptArgs(args, flags []string) (adapted, dropped []string, err error) {
+	adapted = make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			return append(adapted, args[i:]...), dropped, nil
+		}
+		flag, _, _ := strings.Cut(arg, "=")
+		// --help is global, so commands don't list it
+		if !strings.HasPrefix(flag, "--") || flag == "--help" || containsFlag(flags, flag) {
+			adapted = append(adapted, arg)
+			continue
+		}
+		if !containsFlag(droppableFlags, flag) {
+			return nil, nil, fmt.Errorf("%w: the installed shopify %s has no %s flag; %s, or set %s=1 to try anyway", ErrFlagNotSupported, commandOf(args), flag, UpgradeHint, SkipCheckEnvVar)
+		}
+		dropped = append(dropped, flag)
+	}
+	return adapted, dropped, nil
+}
+
+func containsFlag(flags []string, flag string) bool {
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// commandOf returns the command in args, such as "theme list": the leading
+// arguments up to the first flag, at most two of them.
+func commandOf(args []string) string {
+	var words []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") || len(words) == 2 {
+			break
+		}
+		words = append(words, arg)
+	}
+	return strings.Join(words, " ")
+}
+
+// CheckedRunner checks every invocation against the installed Shopify CLI
+// before running it, so an unsupported CLI fails with a hint to upgrade
+// instead of an unknown flag error.
+type CheckedRunner struct {
+	Runner Runner
+	// Version returns the installed CLI's version, usually from a
+	// VersionCache.
+	Version func(ctx context.Context) (Version, error)
+	// Flags returns the long flags the installed CLI lists for a command,
+	// usually from a VersionCache. Nil skips the flag check.
+	Flags func(ctx context.Context, command string) ([]string, error)
+}
+
+// Run checks inv.Args and runs inv, leaving out flags the CLI doesn't have
+// when that is safe. When the version or the flags can't be read the
+// invocation runs unchecked, so the CLI reports any problem itself.
+func (r *CheckedRunner) Run(ctx context.Context, inv Invocation) error {
+	command := commandOf(inv.Args)
+	if command == "" || command == "version" {
+		return r.Runner.Run(ctx, inv)
+	}
+
+	if version, err := r.Version(ctx); err == nil {
+		if err := CheckCLIVersion(version); err != nil {
+			return err
+		}
+	}
+
+	if r.Flags != nil {
+		// A help text without flags is not one stm understands
+		if flags, err := r.Flags(ctx, command); err == nil && len(flags) > 0 {
+			args, dropped, err := AdaptArgs(inv.Args, flags)
+			if err != nil {
+				return err
+			}
+			stderr := inv.Stderr
+			if stderr == nil {
+				stderr = io.Discard
+			}
+			for _, flag := range dropped {
+				fmt.Fprintf(stderr, "stm: the installed shopify %s has no %s flag, so it will ask to confirm itself\n", command, flag)
+			}
+			inv.Args = args
+		}
+	}
+	return r.Runner.Run(ctx, inv)
+}
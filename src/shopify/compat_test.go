@@ -0,0 +1,174 @@
+package shopify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// themeListHelp is the shape of "shopify theme list --help"
+const themeListHelp = `List your remote themes.
+
+USAGE
+  $ shopify theme list [flags]
+
+FLAGS
+  -e, --environment=<value>  The environment to apply to the current command.
+      --json                 Output the theme list as JSON.
+      --name=<value>         Only list themes that contain the given name.
+      --[no-]color           Color the output. Use with --json to get plain JSON.
+  -s, --store=<value>        Store URL.
+`
+
+func TestCheckCLIVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version Version
+		wantErr error
+		want    string
+	}{
+		{name: "supported", version: Version{3, 58, 2}},
+		{name: "first supported", version: Version{3, 0, 0}},
+		{name: "cli 2", version: Version{2, 15, 6}, wantErr: ErrCLITooOld, want: "stm needs 3.0.0 or later"},
+		{name: "next major", version: Version{4, 0, 0}, wantErr: ErrCLITooNew, want: SkipCheckEnvVar + "=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckCLIVersion(tt.version)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("CheckCLIVersion() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckCLIVersion() error = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("CheckCLIVersion() error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHelpFlags(t *testing.T) {
+	want := []string{"--environment", "--json", "--name", "--color", "--no-color", "--store"}
+	if got := ParseHelpFlags(themeListHelp); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseHelpFlags() = %v, want %v", got, want)
+	}
+	if got := ParseHelpFlags("[]"); len(got) != 0 {
+		t.Errorf("ParseHelpFlags(JSON) = %v, want none", got)
+	}
+}
+
+func TestAdaptArgs(t *testing.T) {
+	pushFlags := []string{"--store", "--theme", "--nodelete"}
+	tests := []struct {
+		name        string
+		args        []string
+		flags       []string
+		want        []string
+		wantDropped []string
+		errMsg      string
+	}{
+		{name: "supported", args: ListThemesArgs("s.myshopify.com", ListOptions{Name: "Dawn"}), flags: ParseHelpFlags(themeListHelp), want: ListThemesArgs("s.myshopify.com", ListOptions{Name: "Dawn"})},
+		{name: "flag with value", args: []string{"theme", "push", "--theme=1"}, flags: pushFlags, want: []string{"theme", "push", "--theme=1"}},
+		{name: "short flags pass", args: []string{"theme", "push", "-t", "1"}, flags: pushFlags, want: []string{"theme", "push", "-t", "1"}},
+		{name: "help passes", args: []string{"theme", "push", "--help"}, flags: pushFlags, want: []string{"theme", "push", "--help"}},
+		{
+			name:   "missing flag",
+			args:   []string{"theme", "push", "--store", "s", "--only", "sections/*"},
+			flags:  pushFlags,
+			errMsg: "the installed shopify theme push has no --only flag",
+		},
+		{
+			name:        "missing confirmation flag is dropped",
+			args:        []string{"theme", "push", "--theme", "1", "--allow-live", "--nodelete"},
+			flags:       pushFlags,
+			want:        []string{"theme", "push", "--theme", "1", "--nodelete"},
+			wantDropped: []string{"--allow-live"},
+		},
+		{name: "flags after -- are not checked", args: []string{"theme", "push", "--", "--only"}, flags: pushFlags, want: []string{"theme", "push", "--", "--only"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dropped, err := AdaptArgs(tt.args, tt.flags)
+			if tt.errMsg != "" {
+				if !errors.Is(err, ErrFlagNotSupported) || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("AdaptArgs() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AdaptArgs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AdaptArgs() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(dropped, tt.wantDropped) {
+				t.Errorf("dropped = %v, want %v", dropped, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestCheckedRunner(t *testing.T) {
+	inner := &fakeRunner{}
+	version := Version{2, 15, 6}
+	var versionErr, flagsErr error
+	flags := []string{"--store", "--theme"}
+	runner := &CheckedRunner{
+		Runner:  inner,
+		Version: func(context.Context) (Version, error) { return version, versionErr },
+		Flags:   func(context.Context, string) ([]string, error) { return flags, flagsErr },
+	}
+	ctx := context.Background()
+	run := func(args ...string) (string, error) {
+		var stderr bytes.Buffer
+		err := runner.Run(ctx, Invocation{Args: args, Stdout: io.Discard, Stderr: &stderr})
+		return stderr.String(), err
+	}
+
+	if _, err := run("theme", "push", "--store", "s"); !errors.Is(err, ErrCLITooOld) {
+		t.Errorf("Run() error = %v, want ErrCLITooOld", err)
+	}
+
+	// "shopify version" itself is never checked
+	if _, err := run("version"); err != nil {
+		t.Errorf("Run(version) error = %v", err)
+	}
+
+	version = Version{3, 58, 2}
+	if _, err := run("theme", "push", "--store", "s", "--only", "sections/*"); !errors.Is(err, ErrFlagNotSupported) {
+		t.Errorf("Run() error = %v, want ErrFlagNotSupported", err)
+	}
+	if len(inner.calls) != 1 {
+		t.Fatalf("CLI ran %d times, want 1", len(inner.calls))
+	}
+
+	stderr, err := run("theme", "push", "--theme", "1", "--allow-live")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if want := []string{"theme", "push", "--theme", "1"}; !reflect.DeepEqual(inner.calls[1].Args, want) {
+		t.Errorf("ran %v, want %v", inner.calls[1].Args, want)
+	}
+	if !strings.Contains(stderr, "has no --allow-live flag, so it will ask to confirm itself") {
+		t.Errorf("stderr = %q, want a note about --allow-live", stderr)
+	}
+
+	// When the version or the flags can't be read the CLI runs unchecked
+	versionErr, flagsErr = errors.New("not found"), errors.New("exit status 1")
+	if _, err := run("theme", "push", "--only", "sections/*"); err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+	if len(inner.calls) != 3 {
+		t.Errorf("CLI ran %d times, want 3", len(inner.calls))
+	}
+}
@@ -0,0 +1,22 @@
+package shopify
+
+// DevOptions configures "shopify theme dev".
+type DevOptions struct {
+	// Theme is the theme ID to develop against; empty starts a development
+	// theme.
+	Theme string
+	Port  string
+}
+
+// DevArgs returns the Shopify CLI arguments that start the theme
+// development server for a store.
+func DevArgs(storeID string, opts DevOptions) []string {
+	args := []string{"theme", "dev", "--store", storeID}
+	if opts.Theme != "" {
+		args = append(args, "--theme", opts.Theme)
+	}
+	if opts.Port != "" {
+		args = append(args, "--port", opts.Port)
+	}
+	return args
+}
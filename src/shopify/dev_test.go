@@ -0,0 +1,26 @@
+package shopify
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDevArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts DevOptions
+		want []string
+	}{
+		{name: "development theme", want: []string{"theme", "dev", "--store", "s.myshopify.com"}},
+		{name: "theme", opts: DevOptions{Theme: "123"}, want: []string{"theme", "dev", "--store", "s.myshopify.com", "--theme", "123"}},
+		{name: "port", opts: DevOptions{Port: "9292"}, want: []string{"theme", "dev", "--store", "s.myshopify.com", "--port", "9292"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DevArgs("s.myshopify.com", tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DevArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
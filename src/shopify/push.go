@@ -0,0 +1,50 @@
+package shopify
+
+// SyncOptions configures "shopify theme push" and "shopify theme pull".
+type SyncOptions struct {
+	// Theme is the theme ID to push to or pull from; empty lets the
+	// Shopify CLI prompt for one.
+	Theme string
+	// Only and Ignore are globs passed to --only and --ignore.
+	Only   []string
+	Ignore []string
+	// NoDelete keeps files at the destination that are missing at the
+	// source.
+	NoDelete bool
+	// AllowLive lets push write to the live theme without the CLI's own
+	// confirmation. It is ignored by pull.
+	AllowLive bool
+}
+
+// PushArgs returns the Shopify CLI arguments that upload the project
+// directory to a store's theme.
+func PushArgs(storeID string, opts SyncOptions) []string {
+	args := syncArgs("push", storeID, opts)
+	if opts.AllowLive {
+		args = append(args, "--allow-live")
+	}
+	return args
+}
+
+// PullArgs returns the Shopify CLI arguments that download a store's
+// theme into the project directory.
+func PullArgs(storeID string, opts SyncOptions) []string {
+	return syncArgs("pull", storeID, opts)
+}
+
+func syncArgs(command, storeID string, opts SyncOptions) []string {
+	args := []string{"theme", command, "--store", storeID}
+	if opts.Theme != "" {
+		args = append(args, "--theme", opts.Theme)
+	}
+	for _, pattern := range opts.Only {
+		args = append(args, "--only", pattern)
+	}
+	for _, pattern := range opts.Ignore {
+		args = append(args, "--ignore", pattern)
+	}
+	if opts.NoDelete {
+		args = append(args, "--nodelete")
+	}
+	return args
+}
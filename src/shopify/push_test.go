@@ -0,0 +1,43 @@
+package shopify
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPushArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SyncOptions
+		want []string
+	}{
+		{name: "no theme", want: []string{"theme", "push", "--store", "s.myshopify.com"}},
+		{name: "theme", opts: SyncOptions{Theme: "123"}, want: []string{"theme", "push", "--store", "s.myshopify.com", "--theme", "123"}},
+		{
+			name: "only and ignore",
+			opts: SyncOptions{Only: []string{"sections/*"}, Ignore: []string{"config/settings_data.json", "locales/*"}},
+			want: []string{"theme", "push", "--store", "s.myshopify.com", "--only", "sections/*", "--ignore", "config/settings_data.json", "--ignore", "locales/*"},
+		},
+		{
+			name: "nodelete and allow live",
+			opts: SyncOptions{Theme: "1", NoDelete: true, AllowLive: true},
+			want: []string{"theme", "push", "--store", "s.myshopify.com", "--theme", "1", "--nodelete", "--allow-live"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PushArgs("s.myshopify.com", tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PushArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPullArgs(t *testing.T) {
+	opts := SyncOptions{Theme: "123", Only: []string{"templates/*.json"}, NoDelete: true, AllowLive: true}
+	want := []string{"theme", "pull", "--store", "s.myshopify.com", "--theme", "123", "--only", "templates/*.json", "--nodelete"}
+	if got := PullArgs("s.myshopify.com", opts); !reflect.DeepEqual(got, want) {
+		t.Errorf("PullArgs() = %v, want %v", got, want)
+	}
+}
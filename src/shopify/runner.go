@@ -0,0 +1,53 @@
+package shopify
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// DefaultBinary is the Shopify CLI executable looked up on PATH.
+const DefaultBinary = "shopify"
+
+// Invocation describes a single run of the Shopify CLI.
+type Invocation struct {
+	// Args are passed to the CLI, e.g. {"theme", "list", "--store", id}.
+	Args []string
+	// Dir is the working directory; empty means the current directory.
+	Dir string
+	// Env holds extra KEY=value pairs added to the inherited environment.
+	Env []string
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Runner runs the Shopify CLI. Commands receive a Runner instead of
+// creating processes directly so tests can substitute a fake.
+type Runner interface {
+	Run(ctx context.Context, inv Invocation) error
+}
+
+// CLIRunner runs the real Shopify CLI binary.
+type CLIRunner struct {
+	Binary string
+}
+
+// NewRunner returns a Runner for the shopify binary on PATH.
+func NewRunner() *CLIRunner {
+	return &CLIRunner{Binary: DefaultBinary}
+}
+
+// Run starts the CLI and waits for it to exit. The process is killed if
+// ctx is cancelled first.
+func (r *CLIRunner) Run(ctx context.Context, inv Invocation) error {
+	cmd := exec.CommandContext(ctx, r.Binary, inv.Args...)
+	cmd.Dir = inv.Dir
+	cmd.Env = append(os.Environ(), inv.Env...)
+	cmd.Stdin = inv.Stdin
+	cmd.Stdout = inv.Stdout
+	cmd.Stderr = inv.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,81 @@
+package shopify
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCLIRunner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	t.Parallel()
+
+	dir := t.TempDir()
+	var stdout, stderr bytes.Buffer
+	runner := &CLIRunner{Binary: "sh"}
+
+	err := runner.Run(context.Background(), Invocation{
+		Args:   []string{"-c", `pwd; echo "$STM_TEST_VALUE"; cat; echo oops >&2`},
+		Dir:    dir,
+		Env:    []string{"STM_TEST_VALUE=from-env"},
+		Stdin:  strings.NewReader("from-stdin\n"),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	wantDir, _ := filepath.EvalSymlinks(dir)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("stdout = %q, want 3 lines", stdout.String())
+	}
+	if gotDir, _ := filepath.EvalSymlinks(lines[0]); gotDir != wantDir {
+		t.Errorf("working directory = %s, want %s", lines[0], dir)
+	}
+	if lines[1] != "from-env" {
+		t.Errorf("env value = %q, want from-env", lines[1])
+	}
+	if lines[2] != "from-stdin" {
+		t.Errorf("stdin = %q, want from-stdin", lines[2])
+	}
+	if stderr.String() != "oops\n" {
+		t.Errorf("stderr = %q, want oops", stderr.String())
+	}
+
+	// The parent environment is inherited
+	if os.Getenv("PATH") != "" {
+		stdout.Reset()
+		runner.Run(context.Background(), Invocation{Args: []string{"-c", `echo "$PATH"`}, Stdout: &stdout})
+		if strings.TrimSpace(stdout.String()) != os.Getenv("PATH") {
+			t.Error("PATH was not inherited")
+		}
+	}
+}
+
+func TestCLIRunner_ContextCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := (&CLIRunner{Binary: "sleep"}).Run(ctx, Invocation{Args: []string{"5"}})
+	if err == nil {
+		t.Fatal("expected error from cancelled command")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Run() returned after %v, want prompt cancellation", elapsed)
+	}
+}
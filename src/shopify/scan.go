@@ -0,0 +1,70 @@
+package shopify
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// themeMarkers are files whose presence marks a Shopify theme directory.
+var themeMarkers = []string{
+	filepath.Join("layout", "theme.liquid"),
+	filepath.Join("config", "settings_schema.json"),
+	ThemeConfigFile,
+}
+
+// IsThemeDir reports whether dir looks like a Shopify theme project.
+func IsThemeDir(dir string) bool {
+	for _, marker := range themeMarkers {
+		if info, err := os.Stat(filepath.Join(dir, marker)); err == nil && !info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+// FindThemeDirs returns the theme directories under root, searching at most
+// maxDepth levels down. The search does not descend into theme directories,
+// hidden directories or node_modules, and skips directories it cannot read.
+func FindThemeDirs(root string, maxDepth int) ([]string, error) {
+	root = filepath.Clean(root)
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return filepath.SkipDir
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		name := d.Name()
+		if path != root && (strings.HasPrefix(name, ".") || name == "node_modules") {
+			return filepath.SkipDir
+		}
+		if IsThemeDir(path) {
+			dirs = append(dirs, path)
+			return filepath.SkipDir
+		}
+		if depth(root, path) >= maxDepth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+func depth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return len(strings.Split(rel, string(filepath.Separator)))
+}
@@ -0,0 +1,67 @@
+package shopify
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFile creates path below root with empty content
+func writeFile(t *testing.T, root, path string) {
+	t.Helper()
+	full := filepath.Join(root, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindThemeDirs(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "acme/layout/theme.liquid")
+	writeFile(t, root, "acme/sections/header/layout/theme.liquid") // inside a theme, not searched
+	writeFile(t, root, "clients/globex/config/settings_schema.json")
+	writeFile(t, root, "clients/initech/shopify.theme.toml")
+	writeFile(t, root, "deep/a/b/c/layout/theme.liquid") // deeper than maxDepth
+	writeFile(t, root, ".archive/old/layout/theme.liquid")
+	writeFile(t, root, "tools/node_modules/theme/layout/theme.liquid")
+	writeFile(t, root, "notes/README.md")
+	if err := os.MkdirAll(filepath.Join(root, "acme-layout-dir", "layout", "theme.liquid"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := FindThemeDirs(root, 3)
+	if err != nil {
+		t.Fatalf("FindThemeDirs() error = %v", err)
+	}
+	want := []string{
+		filepath.Join(root, "acme"),
+		filepath.Join(root, "clients", "globex"),
+		filepath.Join(root, "clients", "initech"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindThemeDirs() = %v, want %v", got, want)
+	}
+}
+
+func TestFindThemeDirs_RootIsTheme(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "layout/theme.liquid")
+
+	got, err := FindThemeDirs(root, 3)
+	if err != nil {
+		t.Fatalf("FindThemeDirs() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{root}) {
+		t.Errorf("FindThemeDirs() = %v, want the root", got)
+	}
+}
+
+func TestFindThemeDirs_MissingRoot(t *testing.T) {
+	if _, err := FindThemeDirs(filepath.Join(t.TempDir(), "missing"), 3); err == nil {
+		t.Error("FindThemeDirs() on a missing root succeeded")
+	}
+}
@@ -0,0 +1,156 @@
+// Package shopify wraps the Shopify CLI commands used by stm and decodes
+// their output into typed values.
+package shopify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Theme roles reported by the Shopify CLI
+const (
+	RoleLive        = "live"
+	RoleUnpublished = "unpublished"
+	RoleDevelopment = "development"
+)
+
+// Roles lists every theme role in the order themes are usually shown.
+var Roles = []string{RoleLive, RoleUnpublished, RoleDevelopment}
+
+// Sort keys accepted by SortThemes
+const (
+	SortByRole = "role"
+	SortByName = "name"
+	SortByID   = "id"
+)
+
+// SortKeys lists every key accepted by SortThemes.
+var SortKeys = []string{SortByRole, SortByName, SortByID}
+
+// Theme is a theme as reported by "shopify theme list --json".
+type Theme struct {
+	ID         int64  `json:"id" yaml:"id"`
+	Name       string `json:"name" yaml:"name"`
+	Role       string `json:"role" yaml:"role"`
+	Processing bool   `json:"processing,omitempty" yaml:"processing,omitempty"`
+}
+
+// ListOptions narrows the themes returned by ListThemes.
+type ListOptions struct {
+	// Name filters themes by name, as with the CLI's --name flag.
+	Name string
+}
+
+// ListThemesArgs returns the Shopify CLI arguments that list a store's
+// themes as JSON.
+func ListThemesArgs(storeID string, opts ListOptions) []string {
+	args := []string{"theme", "list", "--store", storeID}
+	if opts.Name != "" {
+		args = append(args, "--name", opts.Name)
+	}
+	return append(args, "--json")
+}
+
+// ListThemes runs "shopify theme list --json" for a store and decodes the
+// result.
+func ListThemes(ctx context.Context, runner Runner, storeID string, opts ListOptions) ([]Theme, error) {
+	var stdout, stderr bytes.Buffer
+	err := runner.Run(ctx, Invocation{
+		Args:   ListThemesArgs(storeID, opts),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("shopify theme list failed: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("shopify theme list failed: %w", err)
+	}
+
+	return ParseThemes(stdout.Bytes())
+}
+
+// ParseThemes decodes the output of "shopify theme list --json".
+func ParseThemes(data []byte) ([]Theme, error) {
+	themes := make([]Theme, 0)
+	if len(bytes.TrimSpace(data)) == 0 {
+		return themes, nil
+	}
+	if err := json.Unmarshal(data, &themes); err != nil {
+		return nil, fmt.Errorf("failed to parse theme list: %w", err)
+	}
+	return themes, nil
+}
+
+// ValidRole reports whether role is a known theme role.
+func ValidRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByRole returns the themes with the given role. An empty role
+// matches every theme.
+func FilterByRole(themes []Theme, role string) []Theme {
+	if role == "" {
+		return themes
+	}
+	filtered := make([]Theme, 0, len(themes))
+	for _, theme := range themes {
+		if theme.Role == role {
+			filtered = append(filtered, theme)
+		}
+	}
+	return filtered
+}
+
+// SortThemes sorts themes in place by one of the SortKeys. Ties are broken
+// by name and then ID.
+func SortThemes(themes []Theme, key string) error {
+	var less func(a, b Theme) bool
+	switch key {
+	case SortByRole, "":
+		less = func(a, b Theme) bool {
+			if a.Role != b.Role {
+				return roleRank(a.Role) < roleRank(b.Role)
+			}
+			return lessByName(a, b)
+		}
+	case SortByName:
+		less = lessByName
+	case SortByID:
+		less = func(a, b Theme) bool { return a.ID < b.ID }
+	default:
+		return fmt.Errorf("invalid sort key %q (use %s)", key, strings.Join(SortKeys, ", "))
+	}
+
+	sort.SliceStable(themes, func(i, j int) bool {
+		return less(themes[i], themes[j])
+	})
+	return nil
+}
+
+func lessByName(a, b Theme) bool {
+	an, bn := strings.ToLower(a.Name), strings.ToLower(b.Name)
+	if an != bn {
+		return an < bn
+	}
+	return a.ID < b.ID
+}
+
+// roleRank orders known roles as in Roles, with unknown roles last.
+func roleRank(role string) int {
+	for i, r := range Roles {
+		if r == role {
+			return i
+		}
+	}
+	return len(Roles)
+}
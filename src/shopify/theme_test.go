@@ -0,0 +1,141 @@
+package shopify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeRunner answers every invocation with fixed output
+type fakeRunner struct {
+	stdout string
+	stderr string
+	err    error
+	calls  []Invocation
+}
+
+func (f *fakeRunner) Run(ctx context.Context, inv Invocation) error {
+	f.calls = append(f.calls, inv)
+	io.WriteString(inv.Stdout, f.stdout)
+	io.WriteString(inv.Stderr, f.stderr)
+	return f.err
+}
+
+func themeIDs(themes []Theme) []int64 {
+	ids := make([]int64, 0, len(themes))
+	for _, theme := range themes {
+		ids = append(ids, theme.ID)
+	}
+	return ids
+}
+
+func TestParseThemes(t *testing.T) {
+	themes, err := ParseThemes([]byte(`[
+		{"id": 1, "name": "Dawn", "role": "live", "processing": false, "extra": "ignored"},
+		{"id": 2, "name": "Draft", "role": "unpublished", "processing": true}
+	]`))
+	if err != nil {
+		t.Fatalf("ParseThemes() error = %v", err)
+	}
+	want := []Theme{
+		{ID: 1, Name: "Dawn", Role: RoleLive},
+		{ID: 2, Name: "Draft", Role: RoleUnpublished, Processing: true},
+	}
+	if !reflect.DeepEqual(themes, want) {
+		t.Errorf("ParseThemes() = %+v, want %+v", themes, want)
+	}
+
+	if themes, err := ParseThemes([]byte("  \n")); err != nil || len(themes) != 0 {
+		t.Errorf("ParseThemes(empty) = %v, %v, want empty list", themes, err)
+	}
+
+	if _, err := ParseThemes([]byte("not json")); err == nil {
+		t.Error("ParseThemes(invalid) succeeded, want error")
+	}
+}
+
+func TestListThemes(t *testing.T) {
+	runner := &fakeRunner{stdout: `[{"id": 7, "name": "Dawn", "role": "live"}]`}
+
+	themes, err := ListThemes(context.Background(), runner, "my-store.myshopify.com", ListOptions{Name: "dawn"})
+	if err != nil {
+		t.Fatalf("ListThemes() error = %v", err)
+	}
+	if len(themes) != 1 || themes[0].ID != 7 {
+		t.Errorf("ListThemes() = %+v", themes)
+	}
+
+	wantArgs := []string{"theme", "list", "--store", "my-store.myshopify.com", "--name", "dawn", "--json"}
+	if len(runner.calls) != 1 || !reflect.DeepEqual(runner.calls[0].Args, wantArgs) {
+		t.Errorf("calls = %+v, want one call with args %v", runner.calls, wantArgs)
+	}
+}
+
+func TestListThemes_Failure(t *testing.T) {
+	runner := &fakeRunner{stderr: "not logged in\n", err: errors.New("exit status 1")}
+
+	_, err := ListThemes(context.Background(), runner, "my-store.myshopify.com", ListOptions{})
+	if err == nil || !strings.Contains(err.Error(), "not logged in") {
+		t.Errorf("ListThemes() error = %v, want stderr in message", err)
+	}
+}
+
+func TestFilterByRole(t *testing.T) {
+	themes := []Theme{
+		{ID: 1, Role: RoleLive},
+		{ID: 2, Role: RoleDevelopment},
+		{ID: 3, Role: RoleDevelopment},
+	}
+
+	if got := themeIDs(FilterByRole(themes, RoleDevelopment)); !reflect.DeepEqual(got, []int64{2, 3}) {
+		t.Errorf("FilterByRole(development) = %v, want [2 3]", got)
+	}
+	if got := themeIDs(FilterByRole(themes, "")); len(got) != 3 {
+		t.Errorf("FilterByRole(\"\") = %v, want all themes", got)
+	}
+}
+
+func TestSortThemes(t *testing.T) {
+	themes := func() []Theme {
+		return []Theme{
+			{ID: 4, Name: "beta", Role: "archived"},
+			{ID: 3, Name: "Zeta", Role: RoleDevelopment},
+			{ID: 1, Name: "alpha", Role: RoleDevelopment},
+			{ID: 2, Name: "Main", Role: RoleLive},
+		}
+	}
+
+	tests := []struct {
+		key     string
+		want    []int64
+		wantErr bool
+	}{
+		{key: SortByRole, want: []int64{2, 1, 3, 4}},
+		{key: "", want: []int64{2, 1, 3, 4}},
+		{key: SortByName, want: []int64{1, 4, 2, 3}},
+		{key: SortByID, want: []int64{1, 2, 3, 4}},
+		{key: "size", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			list := themes()
+			err := SortThemes(list, tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SortThemes() error = %v", err)
+			}
+			if got := themeIDs(list); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortThemes(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package shopify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ThemeConfigFile is the Shopify CLI's per-project config file, which holds
+// named environments such as:
+//
+//	[environments.production]
+//	store = "acme"
+//	theme = "123456789"
+//	ignore = ["config/settings_data.json"]
+const ThemeConfigFile = "shopify.theme.toml"
+
+// Environment is one [environments.<name>] table of shopify.theme.toml.
+// Keys stm does not use, such as password, are not read.
+type Environment struct {
+	Name  string
+	Store string
+	Theme string
+	// Live targets the published theme in place of Theme
+	Live   bool
+	Ignore []string
+	Only   []string
+}
+
+// ReadThemeConfig reads the environments of the shopify.theme.toml in a
+// project directory. A missing file yields no environments.
+func ReadThemeConfig(projectDir string) ([]Environment, error) {
+	data, err := os.ReadFile(filepath.Join(projectDir, ThemeConfigFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseThemeConfig(data)
+}
+
+// ParseThemeConfig decodes the environments of a shopify.theme.toml. Only
+// the subset of TOML the Shopify CLI writes is understood: tables, and
+// keys holding strings, numbers, booleans or arrays of strings.
+func ParseThemeConfig(data []byte) ([]Environment, error) {
+	var envs []Environment
+	var current *Environment
+
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(stripComment(lines[i]))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			name, ok, err := parseEnvironmentHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("%s line %d: %w", ThemeConfigFile, lineNo, err)
+			}
+			current = nil
+			if ok {
+				envs = append(envs, Environment{Name: name})
+				current = &envs[len(envs)-1]
+			}
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("%s line %d: expected key = value", ThemeConfigFile, lineNo)
+		}
+		key = unquoteKey(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		// Arrays may continue over several lines
+		for strings.HasPrefix(value, "[") && !arrayClosed(value) && i+1 < len(lines) {
+			i++
+			value += " " + strings.TrimSpace(stripComment(lines[i]))
+		}
+
+		if current == nil {
+			continue
+		}
+		if err := current.set(key, value); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", ThemeConfigFile, lineNo, err)
+		}
+	}
+	return envs, nil
+}
+
+func (e *Environment) set(key, value string) error {
+	var err error
+	switch key {
+	case "store":
+		e.Store, err = parseScalar(value)
+	case "theme":
+		e.Theme, err = parseScalar(value)
+	case "live":
+		var live string
+		live, err = parseScalar(value)
+		e.Live = live == "true"
+	case "ignore":
+		e.Ignore, err = parseStringArray(value)
+	case "only":
+		e.Only, err = parseStringArray(value)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// parseEnvironmentHeader reports the environment name of a table header
+// such as [environments.production]. Other tables, including tables nested
+// inside an environment, are not environments.
+func parseEnvironmentHeader(line string) (string, bool, error) {
+	if strings.HasPrefix(line, "[[") || !strings.HasSuffix(line, "]") {
+		return "", false, fmt.Errorf("unsupported table header %s", line)
+	}
+	path := strings.TrimSpace(line[1 : len(line)-1])
+	rest, ok := strings.CutPrefix(path, "environments.")
+	if !ok {
+		return "", false, nil
+	}
+
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, `"`) || strings.HasPrefix(rest, "'") {
+		name, err := parseString(rest)
+		if err != nil {
+			return "", false, fmt.Errorf("unsupported table header %s", line)
+		}
+		return name, true, nil
+	}
+	if rest == "" || strings.Contains(rest, ".") {
+		return "", false, nil
+	}
+	return rest, true, nil
+}
+
+// stripComment removes a trailing # comment outside of strings.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquoteKey(key string) string {
+	if unquoted, err := parseString(key); err == nil {
+		return unquoted
+	}
+	return key
+}
+
+// arrayClosed reports whether an array value has its closing bracket.
+func arrayClosed(value string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+// parseScalar returns a string, number or boolean value as a string.
+func parseScalar(value string) (string, error) {
+	if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+		return parseString(value)
+	}
+	if value == "true" || value == "false" {
+		return value, nil
+	}
+	if _, err := strconv.ParseInt(strings.ReplaceAll(value, "_", ""), 10, 64); err == nil {
+		return strings.ReplaceAll(value, "_", ""), nil
+	}
+	return "", fmt.Errorf("unsupported value %s", value)
+}
+
+// parseString decodes a basic "..." or literal '...' TOML string.
+func parseString(value string) (string, error) {
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return value[1 : len(value)-1], nil
+	}
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return "", fmt.Errorf("expected a string, got %s", value)
+	}
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", value)
+	}
+	return unquoted, nil
+}
+
+// parseStringArray decodes an array of strings such as ["a", 'b'].
+func parseStringArray(value string) ([]string, error) {
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return nil, fmt.Errorf("expected an array, got %s", value)
+	}
+	inner := strings.TrimSpace(value[1 : len(value)-1])
+
+	var items []string
+	for inner != "" {
+		end := stringEnd(inner)
+		if end < 0 {
+			return nil, fmt.Errorf("expected a string in %s", value)
+		}
+		item, err := parseString(inner[:end])
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		inner = strings.TrimSpace(inner[end:])
+		if inner == "" {
+			break
+		}
+		if inner[0] != ',' {
+			return nil, fmt.Errorf("expected a comma in %s", value)
+		}
+		inner = strings.TrimSpace(inner[1:])
+	}
+	return items, nil
+}
+
+// stringEnd returns the index just past the string literal at the start of
+// s, or -1 if s does not start with a complete string.
+func stringEnd(s string) int {
+	if s == "" || (s[0] != '"' && s[0] != '\'') {
+		return -1
+	}
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		if s[i] == '\\' && quote == '"' {
+			i++
+			continue
+		}
+		if s[i] == quote {
+			return i + 1
+		}
+	}
+	return -1
+}
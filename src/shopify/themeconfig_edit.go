@@ -0,0 +1,215 @@
+package shopify
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ThemeConfigDoc is a shopify.theme.toml kept as lines, so that merging
+// environments into it preserves comments, key order and keys stm doesn't
+// manage, such as password.
+type ThemeConfigDoc struct {
+	lines []string
+}
+
+// LoadThemeConfigDoc reads the shopify.theme.toml in a project directory.
+// A missing file yields an empty document.
+func LoadThemeConfigDoc(projectDir string) (*ThemeConfigDoc, error) {
+	data, err := os.ReadFile(filepath.Join(projectDir, ThemeConfigFile))
+	if os.IsNotExist(err) {
+		return &ThemeConfigDoc{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseThemeConfigDoc(data)
+}
+
+// ParseThemeConfigDoc checks that data is a shopify.theme.toml stm can read
+// and returns it as a document.
+func ParseThemeConfigDoc(data []byte) (*ThemeConfigDoc, error) {
+	if _, err := ParseThemeConfig(data); err != nil {
+		return nil, err
+	}
+	text := strings.TrimRight(string(data), "\n")
+	if text == "" {
+		return &ThemeConfigDoc{}, nil
+	}
+	return &ThemeConfigDoc{lines: strings.Split(text, "\n")}, nil
+}
+
+// Environments decodes the document's environments.
+func (d *ThemeConfigDoc) Environments() ([]Environment, error) {
+	return ParseThemeConfig(d.Bytes())
+}
+
+// Bytes returns the document's content.
+func (d *ThemeConfigDoc) Bytes() []byte {
+	if len(d.lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(d.lines, "\n") + "\n")
+}
+
+// Write saves the document as the shopify.theme.toml of a project directory.
+func (d *ThemeConfigDoc) Write(projectDir string) error {
+	return os.WriteFile(filepath.Join(projectDir, ThemeConfigFile), d.Bytes(), 0644)
+}
+
+// SetEnvironment merges env into the document. The store, theme, live and
+// ignore keys of an existing table are replaced in place or removed when env
+// leaves them unset; its other keys and comments are kept. A new table is
+// appended to the end of the document.
+func (d *ThemeConfigDoc) SetEnvironment(env Environment) {
+	values := map[string]string{"store": strconv.Quote(env.Store)}
+	if env.Live {
+		values["live"] = "true"
+	} else if env.Theme != "" {
+		values["theme"] = strconv.Quote(env.Theme)
+	}
+	if len(env.Ignore) > 0 {
+		values["ignore"] = formatStringArray(env.Ignore)
+	}
+
+	header, end := d.findEnvironment(env.Name)
+	if header < 0 {
+		d.appendEnvironment(env.Name, values)
+		return
+	}
+
+	// Walk the table bottom up so edits don't move the keys still to visit
+	keys := d.tableKeys(header, end)
+	insertAt := header + 1
+	if len(keys) > 0 {
+		insertAt = keys[len(keys)-1].end + 1
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		key := keys[i]
+		if !isManagedKey(key.name) {
+			continue
+		}
+		value, ok := values[key.name]
+		delete(values, key.name)
+		if !ok {
+			d.splice(key.start, key.end+1)
+			if key.start < insertAt {
+				insertAt -= key.end + 1 - key.start
+			}
+			continue
+		}
+		line := d.lines[key.start]
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		comment := ""
+		if code := stripComment(line); key.start == key.end && len(code) < len(line) {
+			comment = line[len(strings.TrimRight(code, " \t")):]
+		}
+		d.splice(key.start, key.end+1, indent+key.name+" = "+value+comment)
+		if key.start < insertAt {
+			insertAt -= key.end - key.start
+		}
+	}
+
+	var added []string
+	for _, name := range managedKeys {
+		if value, ok := values[name]; ok {
+			added = append(added, name+" = "+value)
+		}
+	}
+	d.splice(insertAt, insertAt, added...)
+}
+
+// managedKeys are the environment keys stm writes, in the order new keys
+// are added.
+var managedKeys = []string{"store", "theme", "live", "ignore"}
+
+func isManagedKey(name string) bool {
+	for _, key := range managedKeys {
+		if key == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *ThemeConfigDoc) appendEnvironment(name string, values map[string]string) {
+	if len(d.lines) > 0 {
+		d.lines = append(d.lines, "")
+	}
+	d.lines = append(d.lines, "[environments."+formatKey(name)+"]")
+	for _, key := range managedKeys {
+		if value, ok := values[key]; ok {
+			d.lines = append(d.lines, key+" = "+value)
+		}
+	}
+}
+
+// splice replaces lines[start:end] with the given lines.
+func (d *ThemeConfigDoc) splice(start, end int, lines ...string) {
+	rest := append(append([]string(nil), lines...), d.lines[end:]...)
+	d.lines = append(d.lines[:start], rest...)
+}
+
+// findEnvironment returns the line of an environment's table header and
+// the line where the next table starts, or -1 if there is no such table.
+func (d *ThemeConfigDoc) findEnvironment(name string) (header, end int) {
+	header = -1
+	for i, line := range d.lines {
+		line = strings.TrimSpace(stripComment(line))
+		if !strings.HasPrefix(line, "[") {
+			continue
+		}
+		if header >= 0 {
+			return header, i
+		}
+		if envName, ok, _ := parseEnvironmentHeader(line); ok && envName == name {
+			header = i
+		}
+	}
+	return header, len(d.lines)
+}
+
+// tableKey is a key of a table, spanning lines start to end inclusive.
+type tableKey struct {
+	name       string
+	start, end int
+}
+
+func (d *ThemeConfigDoc) tableKeys(header, end int) []tableKey {
+	var keys []tableKey
+	for i := header + 1; i < end; i++ {
+		line := strings.TrimSpace(stripComment(d.lines[i]))
+		name, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key := tableKey{name: unquoteKey(strings.TrimSpace(name)), start: i, end: i}
+		value = strings.TrimSpace(value)
+		for strings.HasPrefix(value, "[") && !arrayClosed(value) && key.end+1 < end {
+			key.end++
+			value += " " + strings.TrimSpace(stripComment(d.lines[key.end]))
+		}
+		keys = append(keys, key)
+		i = key.end
+	}
+	return keys
+}
+
+var bareKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func formatKey(key string) string {
+	if bareKeyPattern.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+func formatStringArray(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = strconv.Quote(item)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
@@ -0,0 +1,164 @@
+package shopify
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestThemeConfigDoc_SetEnvironment(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		env  Environment
+		want string
+	}{
+		{
+			name: "empty document",
+			env:  Environment{Name: "production", Store: "acme.myshopify.com", Theme: "123", Ignore: []string{"config/*.json"}},
+			want: `[environments.production]
+store = "acme.myshopify.com"
+theme = "123"
+ignore = ["config/*.json"]
+`,
+		},
+		{
+			name: "new environment is appended",
+			data: `# Shared by the team
+[environments.staging]
+store = "acme"
+`,
+			env: Environment{Name: "eu prod", Store: "acme.myshopify.com", Live: true},
+			want: `# Shared by the team
+[environments.staging]
+store = "acme"
+
+[environments."eu prod"]
+store = "acme.myshopify.com"
+live = true
+`,
+		},
+		{
+			name: "existing environment keeps comments and unknown keys",
+			data: `[environments.production]
+# The main shop
+store = "acme"   # trailing comment
+password = "shptka_secret"
+theme = 111
+ignore = [
+  "a",
+  "b",
+]
+path = "."
+
+[environments.staging]
+store = "acme"
+theme = "222"
+`,
+			env: Environment{Name: "production", Store: "acme.myshopify.com", Theme: "333", Ignore: []string{"config/*.json"}},
+			want: `[environments.production]
+# The main shop
+store = "acme.myshopify.com"   # trailing comment
+password = "shptka_secret"
+theme = "333"
+ignore = ["config/*.json"]
+path = "."
+
+[environments.staging]
+store = "acme"
+theme = "222"
+`,
+		},
+		{
+			name: "unset keys are removed and missing keys added",
+			data: `[environments.production]
+store = "acme"
+theme = "111"
+ignore = ["a"]
+password = "shptka_secret"
+
+# Staging
+[environments.staging]
+store = "acme"
+`,
+			env: Environment{Name: "production", Store: "acme.myshopify.com", Live: true},
+			want: `[environments.production]
+store = "acme.myshopify.com"
+password = "shptka_secret"
+live = true
+
+# Staging
+[environments.staging]
+store = "acme"
+`,
+		},
+		{
+			name: "empty table",
+			data: `[environments.production]
+
+[other]
+key = "value"
+`,
+			env: Environment{Name: "production", Store: "acme.myshopify.com"},
+			want: `[environments.production]
+store = "acme.myshopify.com"
+
+[other]
+key = "value"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ParseThemeConfigDoc([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseThemeConfigDoc() error = %v", err)
+			}
+			doc.SetEnvironment(tt.env)
+			if got := string(doc.Bytes()); got != tt.want {
+				t.Errorf("SetEnvironment() document =\n%s\nwant\n%s", got, tt.want)
+			}
+
+			// The merged environment reads back as given
+			envs, err := doc.Environments()
+			if err != nil {
+				t.Fatalf("Environments() error = %v", err)
+			}
+			for _, env := range envs {
+				if env.Name == tt.env.Name && !reflect.DeepEqual(env, tt.env) {
+					t.Errorf("environment = %+v, want %+v", env, tt.env)
+				}
+			}
+		})
+	}
+}
+
+func TestThemeConfigDoc_Write(t *testing.T) {
+	dir := t.TempDir()
+	doc, err := LoadThemeConfigDoc(dir)
+	if err != nil {
+		t.Fatalf("LoadThemeConfigDoc() without a file error = %v", err)
+	}
+	doc.SetEnvironment(Environment{Name: "production", Store: "acme.myshopify.com", Theme: "123"})
+	if err := doc.Write(dir); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	envs, err := ReadThemeConfig(dir)
+	if err != nil {
+		t.Fatalf("ReadThemeConfig() error = %v", err)
+	}
+	want := []Environment{{Name: "production", Store: "acme.myshopify.com", Theme: "123"}}
+	if !reflect.DeepEqual(envs, want) {
+		t.Errorf("ReadThemeConfig() = %+v, want %+v", envs, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ThemeConfigFile), []byte("[environments.a]\nstore\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadThemeConfigDoc(dir); err == nil {
+		t.Error("LoadThemeConfigDoc() of an invalid file succeeded")
+	}
+}
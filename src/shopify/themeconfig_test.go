@@ -0,0 +1,98 @@
+package shopify
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleThemeConfig = `# Environments for the Acme theme
+[environments.production]
+store = "acme"  # the live shop
+theme = "123456789"
+password = "shptka_secret"
+ignore = ["config/settings_data.json", 'locales/*']
+
+[environments."eu-staging"]
+store = 'acme-eu.myshopify.com'
+theme = 987654321
+only = [
+  "sections/*", # page sections
+  "snippets/#partials/*",
+]
+
+[other]
+store = "not-an-environment"
+
+[environments.production.extra]
+store = "nested"
+`
+
+func TestParseThemeConfig(t *testing.T) {
+	envs, err := ParseThemeConfig([]byte(sampleThemeConfig))
+	if err != nil {
+		t.Fatalf("ParseThemeConfig() error = %v", err)
+	}
+
+	want := []Environment{
+		{
+			Name:   "production",
+			Store:  "acme",
+			Theme:  "123456789",
+			Ignore: []string{"config/settings_data.json", "locales/*"},
+		},
+		{
+			Name:  "eu-staging",
+			Store: "acme-eu.myshopify.com",
+			Theme: "987654321",
+			Only:  []string{"sections/*", "snippets/#partials/*"},
+		},
+	}
+	if !reflect.DeepEqual(envs, want) {
+		t.Errorf("ParseThemeConfig() = %+v, want %+v", envs, want)
+	}
+}
+
+func TestParseThemeConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		errMsg string
+	}{
+		{name: "missing value", data: "[environments.a]\nstore\n", errMsg: "line 2: expected key = value"},
+		{name: "unterminated string", data: "[environments.a]\nstore = \"acme\n", errMsg: "line 2: store: expected a string"},
+		{name: "bad array", data: "[environments.a]\nignore = [\"a\" \"b\"]\n", errMsg: "line 2: ignore: expected a comma"},
+		{name: "array of tables", data: "[[environments]]\n", errMsg: "line 1: unsupported table header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseThemeConfig([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestReadThemeConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	envs, err := ReadThemeConfig(dir)
+	if err != nil || envs != nil {
+		t.Errorf("ReadThemeConfig() without a file = %v, %v; want nothing", envs, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ThemeConfigFile), []byte(sampleThemeConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	envs, err = ReadThemeConfig(dir)
+	if err != nil {
+		t.Fatalf("ReadThemeConfig() error = %v", err)
+	}
+	if len(envs) != 2 {
+		t.Errorf("ReadThemeConfig() = %+v, want 2 environments", envs)
+	}
+}
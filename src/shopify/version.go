@@ -0,0 +1,68 @@
+package shopify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a major.minor.patch release number, such as a Shopify CLI or
+// Node.js version.
+type Version struct {
+	Major, Minor, Patch int
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Less reports whether v is an earlier release than other.
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+var versionPattern = regexp.MustCompile(`v?(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion finds the first version number in s, so that output such as
+// "v18.20.4" or "Current Shopify CLI version: 3.58.2" can be passed as is.
+// A missing patch number is zero.
+func ParseVersion(s string) (Version, error) {
+	match := versionPattern.FindStringSubmatch(s)
+	if match == nil {
+		return Version{}, fmt.Errorf("no version number in %q", strings.TrimSpace(s))
+	}
+	var v Version
+	v.Major, _ = strconv.Atoi(match[1])
+	v.Minor, _ = strconv.Atoi(match[2])
+	if match[3] != "" {
+		v.Patch, _ = strconv.Atoi(match[3])
+	}
+	return v, nil
+}
+
+// CLIVersion runs "shopify version" and parses the installed Shopify CLI's
+// version.
+func CLIVersion(ctx context.Context, runner Runner) (Version, error) {
+	var stdout, stderr bytes.Buffer
+	err := runner.Run(ctx, Invocation{
+		Args:   []string{"version"},
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return Version{}, fmt.Errorf("shopify version failed: %w: %s", err, msg)
+		}
+		return Version{}, fmt.Errorf("shopify version failed: %w", err)
+	}
+	return ParseVersion(stdout.String())
+}
@@ -0,0 +1,73 @@
+package shopify
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Version
+		wantErr bool
+	}{
+		{input: "3.58.2\n", want: Version{3, 58, 2}},
+		{input: "v18.20.4", want: Version{18, 20, 4}},
+		{input: "Current Shopify CLI version: 3.49.0", want: Version{3, 49, 0}},
+		{input: "ruby 3.2.2 (2023-03-30 revision e51014f9c0) [arm64-darwin22]", want: Version{3, 2, 2}},
+		{input: "2.15", want: Version{2, 15, 0}},
+		{input: "unknown", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVersion(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestVersionLess(t *testing.T) {
+	tests := []struct {
+		a, b Version
+		want bool
+	}{
+		{a: Version{3, 58, 2}, b: Version{3, 59, 0}, want: true},
+		{a: Version{3, 59, 0}, b: Version{3, 58, 2}, want: false},
+		{a: Version{2, 99, 99}, b: Version{3, 0, 0}, want: true},
+		{a: Version{3, 0, 1}, b: Version{3, 0, 0}, want: false},
+		{a: Version{3, 0, 0}, b: Version{3, 0, 0}, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Less(tt.b); got != tt.want {
+			t.Errorf("%v.Less(%v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCLIVersion(t *testing.T) {
+	runner := &fakeRunner{stdout: "3.58.2\n"}
+	got, err := CLIVersion(context.Background(), runner)
+	if err != nil {
+		t.Fatalf("CLIVersion() error = %v", err)
+	}
+	if got != (Version{3, 58, 2}) {
+		t.Errorf("CLIVersion() = %v, want 3.58.2", got)
+	}
+	if !reflect.DeepEqual(runner.calls[0].Args, []string{"version"}) {
+		t.Errorf("args = %v, want [version]", runner.calls[0].Args)
+	}
+
+	runner = &fakeRunner{stderr: "boom\n", err: errors.New("exit status 1")}
+	if _, err := CLIVersion(context.Background(), runner); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("CLIVersion() error = %v, want stderr in error", err)
+	}
+}
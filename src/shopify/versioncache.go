@@ -0,0 +1,174 @@
+package shopify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// versionCacheTTL is how long a cached CLI version is trusted when the
+// binary looks unchanged. Wrapper scripts can point at a new release
+// without changing themselves.
+const versionCacheTTL = 24 * time.Hour
+
+// versionTimeout bounds "shopify version" and "shopify <command> --help"
+const versionTimeout = 30 * time.Second
+
+// cachedVersion is the file a VersionCache writes
+type cachedVersion struct {
+	Binary    string    `json:"binary"`
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"modTime"`
+	CheckedAt time.Time `json:"checkedAt"`
+	Version   string    `json:"version"`
+	// Flags are the long flags of each command that has been run, keyed
+	// by command, e.g. "theme list"
+	Flags map[string][]string `json:"flags,omitempty"`
+}
+
+// VersionCache remembers the installed Shopify CLI's version and the flags
+// of its commands between runs, so "shopify version" and "--help" only run
+// when the binary changes.
+type VersionCache struct {
+	// Path is the cache file; empty keeps the version in memory only
+	Path   string
+	Binary string
+	Runner Runner
+
+	once    sync.Once
+	version Version
+	err     error
+
+	// mu guards cached, which is written back when flags are added
+	mu     sync.Mutex
+	cached cachedVersion
+}
+
+// NewVersionCache returns a cache for the shopify binary run by runner,
+// stored in the user's cache directory.
+func NewVersionCache(runner Runner) *VersionCache {
+	cache := &VersionCache{Binary: DefaultBinary, Runner: runner}
+	if dir, err := os.UserCacheDir(); err == nil {
+		cache.Path = filepath.Join(dir, "shopify-theme-manager", "cli-version.json")
+	}
+	return cache
+}
+
+// Version returns the installed CLI's version, reading it at most once per
+// process.
+func (c *VersionCache) Version(ctx context.Context) (Version, error) {
+	c.once.Do(func() {
+		c.version, c.err = c.load(ctx)
+	})
+	return c.version, c.err
+}
+
+func (c *VersionCache) load(ctx context.Context) (Version, error) {
+	path, err := exec.LookPath(c.Binary)
+	if err != nil {
+		return Version{}, err
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Version{}, err
+	}
+
+	if cached, ok := c.read(); ok &&
+		cached.Binary == path &&
+		cached.Size == info.Size() &&
+		cached.ModTime.Equal(info.ModTime()) &&
+		time.Since(cached.CheckedAt) < versionCacheTTL {
+		if version, err := ParseVersion(cached.Version); err == nil {
+			c.cached = cached
+			return version, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, versionTimeout)
+	defer cancel()
+	version, err := CLIVersion(ctx, c.Runner)
+	if err != nil {
+		return Version{}, err
+	}
+
+	// Caching is best effort; the version is read again next time
+	c.cached = cachedVersion{
+		Binary:    path,
+		Size:      info.Size(),
+		ModTime:   info.ModTime(),
+		CheckedAt: time.Now(),
+		Version:   version.String(),
+	}
+	c.write(c.cached)
+	return version, nil
+}
+
+// Flags returns the long flags the installed CLI lists in
+// "shopify <command> --help". Like the version they are read once per CLI
+// release.
+func (c *VersionCache) Flags(ctx context.Context, command string) ([]string, error) {
+	if _, err := c.Version(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if flags, ok := c.cached.Flags[command]; ok {
+		return flags, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, versionTimeout)
+	defer cancel()
+	var stdout bytes.Buffer
+	err := c.Runner.Run(ctx, Invocation{
+		Args:   append(strings.Fields(command), "--help"),
+		Stdout: &stdout,
+		Stderr: io.Discard,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	flags := ParseHelpFlags(stdout.String())
+	if c.cached.Flags == nil {
+		c.cached.Flags = make(map[string][]string)
+	}
+	c.cached.Flags[command] = flags
+	c.write(c.cached)
+	return flags, nil
+}
+
+func (c *VersionCache) read() (cachedVersion, bool) {
+	var cached cachedVersion
+	if c.Path == "" {
+		return cached, false
+	}
+	data, err := os.ReadFile(c.Path)
+	if err != nil {
+		return cached, false
+	}
+	return cached, json.Unmarshal(data, &cached) == nil
+}
+
+func (c *VersionCache) write(cached cachedVersion) {
+	if c.Path == "" {
+		return
+	}
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0755); err == nil {
+		os.WriteFile(c.Path, data, 0644)
+	}
+}
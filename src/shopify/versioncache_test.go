@@ -0,0 +1,146 @@
+package shopify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// installFakeBinary puts an executable named shopify first on PATH and
+// returns its path.
+func installFakeBinary(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs an executable without extension")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, DefaultBinary)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	return path
+}
+
+func TestVersionCache(t *testing.T) {
+	binary := installFakeBinary(t)
+	cachePath := filepath.Join(t.TempDir(), "cli-version.json")
+	runner := &fakeRunner{stdout: "3.58.2\n"}
+	newCache := func() *VersionCache {
+		return &VersionCache{Path: cachePath, Binary: DefaultBinary, Runner: runner}
+	}
+	ctx := context.Background()
+
+	cache := newCache()
+	for i := 0; i < 2; i++ {
+		got, err := cache.Version(ctx)
+		if err != nil {
+			t.Fatalf("Version() error = %v", err)
+		}
+		if got != (Version{3, 58, 2}) {
+			t.Errorf("Version() = %v, want 3.58.2", got)
+		}
+	}
+	if len(runner.calls) != 1 {
+		t.Errorf("shopify version ran %d times, want 1", len(runner.calls))
+	}
+
+	// A new process reads the cache file
+	if _, err := newCache().Version(ctx); err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	if len(runner.calls) != 1 {
+		t.Errorf("shopify version ran %d times, want 1", len(runner.calls))
+	}
+
+	// Upgrading the CLI changes the binary, so the version is read again
+	runner.stdout = "3.59.0\n"
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(binary, later, later); err != nil {
+		t.Fatal(err)
+	}
+	got, err := newCache().Version(ctx)
+	if err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	if got != (Version{3, 59, 0}) {
+		t.Errorf("Version() = %v, want 3.59.0", got)
+	}
+	if len(runner.calls) != 2 {
+		t.Errorf("shopify version ran %d times, want 2", len(runner.calls))
+	}
+}
+
+func TestVersionCache_MissingBinary(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	runner := &fakeRunner{stdout: "3.58.2\n"}
+	cache := &VersionCache{Binary: DefaultBinary, Runner: runner}
+
+	if _, err := cache.Version(context.Background()); err == nil {
+		t.Error("Version() succeeded, want error")
+	}
+	if len(runner.calls) != 0 {
+		t.Errorf("shopify version ran %d times, want 0", len(runner.calls))
+	}
+}
+
+func TestVersionCache_Flags(t *testing.T) {
+	binary := installFakeBinary(t)
+	cachePath := filepath.Join(t.TempDir(), "cli-version.json")
+	runner := &fakeRunner{stdout: "3.58.2\n"}
+	newCache := func() *VersionCache {
+		return &VersionCache{Path: cachePath, Binary: DefaultBinary, Runner: runner}
+	}
+	ctx := context.Background()
+
+	cache := newCache()
+	if _, err := cache.Version(ctx); err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	runner.stdout = themeListHelp
+	for i := 0; i < 2; i++ {
+		flags, err := cache.Flags(ctx, "theme list")
+		if err != nil {
+			t.Fatalf("Flags() error = %v", err)
+		}
+		if len(flags) != 6 {
+			t.Errorf("Flags() = %v, want the 6 flags of theme list", flags)
+		}
+	}
+	if len(runner.calls) != 2 {
+		t.Fatalf("shopify ran %d times, want 2", len(runner.calls))
+	}
+	if got := runner.calls[1].Args; !reflect.DeepEqual(got, []string{"theme", "list", "--help"}) {
+		t.Errorf("ran %v, want theme list --help", got)
+	}
+
+	// A new process reads the flags from the cache file
+	if flags, err := newCache().Flags(ctx, "theme list"); err != nil || len(flags) != 6 {
+		t.Errorf("Flags() = %v, %v, want the cached flags", flags, err)
+	}
+	if len(runner.calls) != 2 {
+		t.Errorf("shopify ran %d times, want 2", len(runner.calls))
+	}
+
+	// A new CLI release may have other flags, so they are read again
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(binary, later, later); err != nil {
+		t.Fatal(err)
+	}
+	runner.stdout = "3.59.0\n"
+	cache = newCache()
+	if _, err := cache.Version(ctx); err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	runner.stdout = themeListHelp
+	if _, err := cache.Flags(ctx, "theme list"); err != nil {
+		t.Fatalf("Flags() error = %v", err)
+	}
+	if len(runner.calls) != 4 {
+		t.Errorf("shopify ran %d times, want 4", len(runner.calls))
+	}
+}